@@ -2,6 +2,14 @@ package app
 
 import (
 	"bytes"
+	"container/heap"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -14,70 +22,458 @@ const (
 var errTooManyUnconfirmedTx = sdk.NewError(CodeSpaceUnconfirmedLimit, CodeTooManyUnconfirmedTx, "Too Many Unconfirmed Transactions")
 
 const (
-	SameTxExist      = 1
-	OtherTxExist     = 2
-	NoTxExist        = 3
-	SweepPeriod      = 15 * 60 // 15 minutes
-	DefaultLimitTime = 60      // a minute
+	SameTxExist                = 1
+	OtherTxExist               = 2
+	NoTxExist                  = 3
+	DefaultMaxExpiredPerCommit = 10000 // expired txs removed in a commit at most
+	DefaultLimitTime           = 60    // a minute
+	DefaultQuota               = 1     // unconfirmed txs allowed for an account
+	UnlimitedQuota             = 0
+
+	numUnconfirmedTxShards = 16
 )
 
+// FeeTier lets a tx whose gas price is at least Multiple times the min gas price
+// be accepted if its signers have less than Quota unconfirmed txs
+type FeeTier struct {
+	Multiple sdk.Dec
+	Quota    int
+}
+
 type UnconfirmedTx struct {
 	HashID    []byte
+	Sequence  uint64
 	Timestamp int64
 }
 
+// a signer of a delivered tx, with its sequence after the tx
+type deliveredTxSigner struct {
+	Addr     sdk.AccAddress
+	HashID   []byte
+	Sequence uint64
+}
+
+// an entry of the expiry queue, there is exactly one for each unconfirmed tx of an account
+type expiryItem struct {
+	key       string
+	hashid    []byte
+	timestamp int64
+	index     int // in the heap, for heap.Remove
+}
+
+// expiryHeap orders the unconfirmed txs by the time they were added. As all the txs
+// share the same limit time, it is also the order in which they expire.
+type expiryHeap []*expiryItem
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].timestamp < h[j].timestamp }
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *expiryHeap) Push(x interface{}) {
+	item := x.(*expiryItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	item.index = -1
+	return item
+}
+
+// a shard holds the unconfirmed txs of the accounts hashed to it
+type unconfirmedTxShard struct {
+	mtx sync.RWMutex
+	// the unconfirmed txs of each account, ordered by sequence
+	auMap  map[string][]UnconfirmedTx
+	expiry expiryHeap
+	// the items in expiry, keyed by expiryKey
+	items map[string]*expiryItem
+}
+
+func newUnconfirmedTxShard() *unconfirmedTxShard {
+	return &unconfirmedTxShard{
+		auMap: make(map[string][]UnconfirmedTx),
+		items: make(map[string]*expiryItem),
+	}
+}
+
+func expiryKey(key string, hashid []byte) string {
+	return key + string(hashid)
+}
+
+// Account2UnconfirmedTx tracks the unconfirmed txs of each account. It is safe for concurrent use:
+// the accounts are spread over shards, each of which is protected by its own lock. Expired txs are
+// removed incrementally at each commit in the order they expire, at most maxExpiredPerCommit of them,
+// so that a commit never scans the whole tracker.
 type Account2UnconfirmedTx struct {
-	auMap         map[string]UnconfirmedTx
-	limitTime     int64
-	removeList    []sdk.AccAddress
-	lastSweepTime int64
+	shards              [numUnconfirmedTxShards]*unconfirmedTxShard
+	limitTime           int64 // atomic
+	maxExpiredPerCommit int64 // atomic
+	lastSweepTime       int64 // atomic
+	nextShard           int   // the shard from which expiring starts, only used by CommitRemove
+
+	quotaMtx     sync.RWMutex
+	defaultQuota int
+	quotas       map[string]int
+	feeTiers     []FeeTier // ordered by Multiple
+
+	removeMtx  sync.Mutex
+	removeList []deliveredTxSigner
 }
 
 func NewAccount2UnconfirmedTx(limitTime int64) *Account2UnconfirmedTx {
-	return &Account2UnconfirmedTx{
-		auMap:         make(map[string]UnconfirmedTx),
-		limitTime:     limitTime,
-		removeList:    make([]sdk.AccAddress, 0, 5000),
-		lastSweepTime: 0,
+	acc2unc := &Account2UnconfirmedTx{
+		limitTime:           limitTime,
+		maxExpiredPerCommit: DefaultMaxExpiredPerCommit,
+		defaultQuota:        DefaultQuota,
+		quotas:              make(map[string]int),
+		removeList:          make([]deliveredTxSigner, 0, 5000),
+	}
+	for i := range acc2unc.shards {
+		acc2unc.shards[i] = newUnconfirmedTxShard()
+	}
+	return acc2unc
+}
+
+func (acc2unc *Account2UnconfirmedTx) getShard(key string) *unconfirmedTxShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return acc2unc.shards[h.Sum32()%numUnconfirmedTxShards]
+}
+
+// Reset forgets all the unconfirmed txs
+func (acc2unc *Account2UnconfirmedTx) Reset() {
+	for _, shard := range acc2unc.shards {
+		shard.mtx.Lock()
+		shard.auMap = make(map[string][]UnconfirmedTx)
+		shard.expiry = nil
+		shard.items = make(map[string]*expiryItem)
+		shard.mtx.Unlock()
 	}
+	acc2unc.ClearRemoveList()
 }
 
+// SetLimitTime sets how long (in seconds) an unconfirmed tx blocks the other txs of its signers
+func (acc2unc *Account2UnconfirmedTx) SetLimitTime(limitTime int64) {
+	atomic.StoreInt64(&acc2unc.limitTime, limitTime)
+}
+
+func (acc2unc *Account2UnconfirmedTx) getLimitTime() int64 {
+	return atomic.LoadInt64(&acc2unc.limitTime)
+}
+
+// SetMaxExpiredPerCommit sets how many expired txs can be removed in a commit at most
+func (acc2unc *Account2UnconfirmedTx) SetMaxExpiredPerCommit(n int64) {
+	atomic.StoreInt64(&acc2unc.maxExpiredPerCommit, n)
+}
+
+// SetQuotas replaces the default quota and all the overrides, which are keyed by string(addr)
+func (acc2unc *Account2UnconfirmedTx) SetQuotas(defaultQuota int, overrides map[string]int) {
+	quotas := make(map[string]int, len(overrides))
+	for addr, quota := range overrides {
+		quotas[addr] = quota
+	}
+	acc2unc.quotaMtx.Lock()
+	defer acc2unc.quotaMtx.Unlock()
+	acc2unc.defaultQuota = defaultQuota
+	acc2unc.quotas = quotas
+}
+
+// SetDefaultQuota sets how many unconfirmed txs an account can have, UnlimitedQuota means no limit
+func (acc2unc *Account2UnconfirmedTx) SetDefaultQuota(quota int) {
+	acc2unc.quotaMtx.Lock()
+	defer acc2unc.quotaMtx.Unlock()
+	acc2unc.defaultQuota = quota
+}
+
+// SetQuota overrides the default quota for addr, UnlimitedQuota whitelists it
+func (acc2unc *Account2UnconfirmedTx) SetQuota(addr sdk.AccAddress, quota int) {
+	acc2unc.quotaMtx.Lock()
+	defer acc2unc.quotaMtx.Unlock()
+	acc2unc.quotas[string(addr)] = quota
+}
+
+// SetFeeTiers replaces the fee tiers, which must be ordered by Multiple
+func (acc2unc *Account2UnconfirmedTx) SetFeeTiers(feeTiers []FeeTier) {
+	acc2unc.quotaMtx.Lock()
+	defer acc2unc.quotaMtx.Unlock()
+	acc2unc.feeTiers = append([]FeeTier(nil), feeTiers...)
+}
+
+func (acc2unc *Account2UnconfirmedTx) getQuota(addr sdk.AccAddress) int {
+	acc2unc.quotaMtx.RLock()
+	defer acc2unc.quotaMtx.RUnlock()
+	if quota, ok := acc2unc.quotas[string(addr)]; ok {
+		return quota
+	}
+	return acc2unc.defaultQuota
+}
+
+// getQuotaForTx returns the quota of addr for a tx whose gas price is multiple times the min gas price.
+// The highest fee tier reached raises the quota, but never lowers it.
+func (acc2unc *Account2UnconfirmedTx) getQuotaForTx(addr sdk.AccAddress, multiple sdk.Dec) int {
+	quota := acc2unc.getQuota(addr)
+	if quota == UnlimitedQuota || multiple.IsNil() {
+		return quota
+	}
+
+	acc2unc.quotaMtx.RLock()
+	defer acc2unc.quotaMtx.RUnlock()
+	for i := len(acc2unc.feeTiers) - 1; i >= 0; i-- {
+		tier := acc2unc.feeTiers[i]
+		if multiple.LT(tier.Multiple) {
+			continue
+		}
+		if tier.Quota == UnlimitedQuota || tier.Quota > quota {
+			return tier.Quota
+		}
+		break
+	}
+	return quota
+}
+
+func (acc2unc *Account2UnconfirmedTx) isExpired(unconfirmedTx UnconfirmedTx, timestamp int64) bool {
+	return timestamp-unconfirmedTx.Timestamp > acc2unc.getLimitTime()
+}
+
+// Lookup tells whether addr can have the tx hashid. Lookup and the following Add are not atomic,
+// so concurrent CheckTx calls for the same account may exceed its quota slightly.
 func (acc2unc *Account2UnconfirmedTx) Lookup(addr sdk.AccAddress, hashid []byte, timestamp int64) int {
-	unconfirmedTx, ok := acc2unc.auMap[string(addr)]
-	expired := timestamp-unconfirmedTx.Timestamp > acc2unc.limitTime
-	if !ok || expired {
-		return NoTxExist
+	return acc2unc.LookupWithFee(addr, hashid, timestamp, sdk.Dec{})
+}
+
+// LookupWithFee is like Lookup, with the fee tiers applied to a tx whose gas price is multiple times the min gas price
+func (acc2unc *Account2UnconfirmedTx) LookupWithFee(addr sdk.AccAddress, hashid []byte, timestamp int64, multiple sdk.Dec) int {
+	key := string(addr)
+	shard := acc2unc.getShard(key)
+	count := 0
+	shard.mtx.RLock()
+	for _, unconfirmedTx := range shard.auMap[key] {
+		if acc2unc.isExpired(unconfirmedTx, timestamp) {
+			continue
+		}
+		if bytes.Equal(unconfirmedTx.HashID, hashid) {
+			shard.mtx.RUnlock()
+			return SameTxExist
+		}
+		count++
 	}
-	if bytes.Equal(unconfirmedTx.HashID, hashid) {
-		return SameTxExist
+	shard.mtx.RUnlock()
+
+	quota := acc2unc.getQuotaForTx(addr, multiple)
+	if quota != UnlimitedQuota && count >= quota {
+		return OtherTxExist
 	}
-	return OtherTxExist
+	return NoTxExist
 }
 
-func (acc2unc *Account2UnconfirmedTx) Add(addr sdk.AccAddress, hashid []byte, timestamp int64) {
-	acc2unc.auMap[string(addr)] = UnconfirmedTx{HashID: hashid, Timestamp: timestamp}
+// Add records a tx of addr which uses sequence. A former tx with the same sequence is replaced.
+func (acc2unc *Account2UnconfirmedTx) Add(addr sdk.AccAddress, hashid []byte, sequence uint64, timestamp int64) {
+	key := string(addr)
+	shard := acc2unc.getShard(key)
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	txs := shard.removeTx(key, shard.auMap[key], hashid)
+	newTx := UnconfirmedTx{HashID: hashid, Sequence: sequence, Timestamp: timestamp}
+	i := sort.Search(len(txs), func(i int) bool { return txs[i].Sequence >= sequence })
+	if i < len(txs) && txs[i].Sequence == sequence {
+		shard.removeExpiryItems(key, txs[i:i+1])
+		txs[i] = newTx
+	} else {
+		txs = append(txs, UnconfirmedTx{})
+		copy(txs[i+1:], txs[i:])
+		txs[i] = newTx
+	}
+	shard.auMap[key] = txs
+	item := &expiryItem{key: key, hashid: hashid, timestamp: timestamp}
+	heap.Push(&shard.expiry, item)
+	shard.items[expiryKey(key, hashid)] = item
 }
 
-func (acc2unc *Account2UnconfirmedTx) AddToRemoveList(addrs []sdk.AccAddress) {
-	acc2unc.removeList = append(acc2unc.removeList, addrs...)
+// removeTx removes the tx hashid from txs, the ones of key, together with its expiry item
+func (shard *unconfirmedTxShard) removeTx(key string, txs []UnconfirmedTx, hashid []byte) []UnconfirmedTx {
+	for i, unconfirmedTx := range txs {
+		if bytes.Equal(unconfirmedTx.HashID, hashid) {
+			shard.removeExpiryItems(key, txs[i:i+1])
+			return append(txs[:i], txs[i+1:]...)
+		}
+	}
+	return txs
 }
 
-func (acc2unc *Account2UnconfirmedTx) CommitRemove(timestamp int64) {
-	for _, addr := range acc2unc.removeList {
-		s := string(addr)
-		delete(acc2unc.auMap, s) // will do nothing if key not existing
-	}
-	if timestamp-acc2unc.lastSweepTime > SweepPeriod {
-		for acc, unconfirmedTx := range acc2unc.auMap {
-			expired := timestamp-unconfirmedTx.Timestamp > acc2unc.limitTime
-			if expired {
-				delete(acc2unc.auMap, acc)
-			}
+// removeExpiryItems must be called when txs are removed from the ones of key,
+// so that the expiry queue only holds the items of the unconfirmed txs
+func (shard *unconfirmedTxShard) removeExpiryItems(key string, txs []UnconfirmedTx) {
+	for _, unconfirmedTx := range txs {
+		k := expiryKey(key, unconfirmedTx.HashID)
+		if item, ok := shard.items[k]; ok {
+			heap.Remove(&shard.expiry, item.index)
+			delete(shard.items, k)
 		}
-		acc2unc.lastSweepTime = timestamp
+	}
+}
+
+// AddToRemoveList records that the tx hashid signed by addr is delivered, and the sequence of addr after it.
+// At commit, the tx and the txs of addr using smaller sequences are removed.
+func (acc2unc *Account2UnconfirmedTx) AddToRemoveList(addr sdk.AccAddress, hashid []byte, sequence uint64) {
+	acc2unc.removeMtx.Lock()
+	defer acc2unc.removeMtx.Unlock()
+	acc2unc.removeList = append(acc2unc.removeList, deliveredTxSigner{Addr: addr, HashID: hashid, Sequence: sequence})
+}
+
+func (acc2unc *Account2UnconfirmedTx) CommitRemove(timestamp int64) {
+	acc2unc.removeMtx.Lock()
+	removeList := acc2unc.removeList
+	acc2unc.removeMtx.Unlock()
+
+	for _, signer := range removeList {
+		key := string(signer.Addr)
+		shard := acc2unc.getShard(key)
+		shard.mtx.Lock()
+		shard.removeConfirmed(key, signer.HashID, signer.Sequence)
+		shard.mtx.Unlock()
+	}
+	acc2unc.removeExpired(timestamp)
+}
+
+// removeExpired visits the shards in turn, starting from a different one at each commit
+func (acc2unc *Account2UnconfirmedTx) removeExpired(timestamp int64) {
+	budget := atomic.LoadInt64(&acc2unc.maxExpiredPerCommit)
+	deadline := timestamp - acc2unc.getLimitTime()
+	for i := 0; i < numUnconfirmedTxShards && budget > 0; i++ {
+		shard := acc2unc.shards[(acc2unc.nextShard+i)%numUnconfirmedTxShards]
+		shard.mtx.Lock()
+		budget -= shard.removeExpired(deadline, budget)
+		shard.mtx.Unlock()
+	}
+	acc2unc.nextShard = (acc2unc.nextShard + 1) % numUnconfirmedTxShards
+	atomic.StoreInt64(&acc2unc.lastSweepTime, timestamp)
+}
+
+// removeExpired removes at most budget txs added before deadline, and returns the number removed
+func (shard *unconfirmedTxShard) removeExpired(deadline int64, budget int64) int64 {
+	var n int64
+	for ; n < budget && len(shard.expiry) != 0 && shard.expiry[0].timestamp < deadline; n++ {
+		item := heap.Pop(&shard.expiry).(*expiryItem)
+		delete(shard.items, expiryKey(item.key, item.hashid))
+		shard.setTxs(item.key, shard.removeTx(item.key, shard.auMap[item.key], item.hashid))
+	}
+	return n
+}
+
+func (shard *unconfirmedTxShard) removeConfirmed(key string, hashid []byte, sequence uint64) {
+	txs, ok := shard.auMap[key]
+	if !ok {
+		return
+	}
+	txs = shard.removeTx(key, txs, hashid)
+	i := sort.Search(len(txs), func(i int) bool { return txs[i].Sequence >= sequence })
+	shard.removeExpiryItems(key, txs[:i])
+	shard.setTxs(key, txs[i:])
+}
+
+func (shard *unconfirmedTxShard) setTxs(key string, txs []UnconfirmedTx) {
+	if len(txs) == 0 {
+		delete(shard.auMap, key)
+	} else {
+		shard.auMap[key] = txs
 	}
 }
 
 func (acc2unc *Account2UnconfirmedTx) ClearRemoveList() {
+	acc2unc.removeMtx.Lock()
+	defer acc2unc.removeMtx.Unlock()
 	acc2unc.removeList = acc2unc.removeList[:0]
 }
+
+// parseQuotaOverrides parses overrides like "addr1:5", in which 0 means unlimited
+func parseQuotaOverrides(overrides []string) (map[string]int, error) {
+	res := make(map[string]int, len(overrides))
+	for _, item := range overrides {
+		item = strings.TrimSpace(item)
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid quota override: %s", item)
+		}
+		addr, err := sdk.AccAddressFromBech32(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		quota, err := strconv.Atoi(parts[1])
+		if err != nil || quota < 0 {
+			return nil, fmt.Errorf("invalid quota override: %s", item)
+		}
+		res[string(addr)] = quota
+	}
+	return res, nil
+}
+
+// parseFeeTiers parses tiers like "10:2", which means a tx paying at least 10 times the min gas price
+// is accepted if its signers have less than 2 unconfirmed txs. The result is ordered by Multiple.
+func parseFeeTiers(tiers []string) ([]FeeTier, error) {
+	res := make([]FeeTier, 0, len(tiers))
+	for _, item := range tiers {
+		item = strings.TrimSpace(item)
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid fee tier: %s", item)
+		}
+		multiple, decErr := sdk.NewDecFromStr(parts[0])
+		if decErr != nil || !multiple.IsPositive() {
+			return nil, fmt.Errorf("invalid fee tier: %s", item)
+		}
+		quota, err := strconv.Atoi(parts[1])
+		if err != nil || quota < 0 {
+			return nil, fmt.Errorf("invalid fee tier: %s", item)
+		}
+		res = append(res, FeeTier{Multiple: multiple, Quota: quota})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Multiple.LT(res[j].Multiple) })
+	return res, nil
+}
+
+// GetTxs returns a copy of the unconfirmed txs of addr, ordered by sequence
+func (acc2unc *Account2UnconfirmedTx) GetTxs(addr sdk.AccAddress) []UnconfirmedTx {
+	key := string(addr)
+	shard := acc2unc.getShard(key)
+	shard.mtx.RLock()
+	defer shard.mtx.RUnlock()
+	return append([]UnconfirmedTx(nil), shard.auMap[key]...)
+}
+
+// ExpireTime returns when unconfirmedTx stops blocking the other txs of its signers
+func (acc2unc *Account2UnconfirmedTx) ExpireTime(unconfirmedTx UnconfirmedTx) int64 {
+	return unconfirmedTx.Timestamp + acc2unc.getLimitTime()
+}
+
+// UnconfirmedTxStats is a summary of the tracker, times are unix seconds
+type UnconfirmedTxStats struct {
+	Accounts      int   `json:"accounts"`
+	Txs           int   `json:"txs"`
+	ExpiryQueue   int   `json:"expiry_queue"`
+	LastSweepTime int64 `json:"last_sweep_time"`
+	RemoveListLen int   `json:"remove_list_len"`
+}
+
+func (acc2unc *Account2UnconfirmedTx) GetStats() UnconfirmedTxStats {
+	stats := UnconfirmedTxStats{LastSweepTime: atomic.LoadInt64(&acc2unc.lastSweepTime)}
+	for _, shard := range acc2unc.shards {
+		shard.mtx.RLock()
+		stats.Accounts += len(shard.auMap)
+		stats.ExpiryQueue += len(shard.expiry)
+		for _, txs := range shard.auMap {
+			stats.Txs += len(txs)
+		}
+		shard.mtx.RUnlock()
+	}
+	acc2unc.removeMtx.Lock()
+	stats.RemoveListLen = len(acc2unc.removeList)
+	acc2unc.removeMtx.Unlock()
+	return stats
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"bytes"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,7 +31,7 @@ func TestAccount2UnconfirmedTx(t *testing.T) {
 	// app
 	app := initAppWithBaseAccounts(acc0, acc1)
 	app.enableUnconfirmedLimit = true
-	app.account2UnconfirmedTx.limitTime = 100
+	app.account2UnconfirmedTx.SetLimitTime(100)
 	// begin block
 	now := time.Now()
 	header := abci.Header{Height: 1, Time: now}
@@ -45,12 +47,12 @@ func TestAccount2UnconfirmedTx(t *testing.T) {
 	hashID := tmtypes.Tx(txBytes).Hash()
 	exist := app.account2UnconfirmedTx.Lookup(fromAddr, hashID, header.Time.Unix())
 	require.Equal(t, exist, NoTxExist)
-	app.account2UnconfirmedTx.Add(fromAddr, hashID, header.Time.Unix())
+	app.account2UnconfirmedTx.Add(fromAddr, hashID, 0, header.Time.Unix())
 
 	//deliver tx
 	result := app.Deliver(tx)
 	require.Equal(t, errors.CodeOK, result.Code)
-	acc := app.account2UnconfirmedTx.removeList[0]
+	acc := app.account2UnconfirmedTx.removeList[0].Addr
 	require.True(t, bytes.Equal(acc, fromAddr))
 
 	//build another address tx
@@ -60,7 +62,7 @@ func TestAccount2UnconfirmedTx(t *testing.T) {
 	hashIDAnother := tmtypes.Tx(txBytes2).Hash()
 	exist = app.account2UnconfirmedTx.Lookup(fromAddr2, hashIDAnother, header.Time.Unix())
 	require.Equal(t, exist, NoTxExist)
-	app.account2UnconfirmedTx.Add(fromAddr, hashIDAnother, header.Time.Unix())
+	app.account2UnconfirmedTx.Add(fromAddr, hashIDAnother, 0, header.Time.Unix())
 
 	//build another same address tx
 	msg = bankx.NewMsgSend(fromAddr, toAddr, coins, 0)
@@ -77,7 +79,7 @@ func TestAccount2UnconfirmedTx(t *testing.T) {
 	//end block
 	app.EndBlock(abci.RequestEndBlock{Height: 1})
 	app.Commit()
-	require.Equal(t, len(app.account2UnconfirmedTx.auMap), 0)
+	require.Equal(t, app.account2UnconfirmedTx.GetStats().Accounts, 0)
 
 	//next block
 	header = abci.Header{Height: 2}
@@ -92,5 +94,225 @@ func TestAccount2UnconfirmedTx(t *testing.T) {
 	hashID3 := tmtypes.Tx(txBytes).Hash()
 	exist = app.account2UnconfirmedTx.Lookup(fromAddr, hashID3, header.Time.Unix())
 	require.Equal(t, exist, NoTxExist)
-	app.account2UnconfirmedTx.Add(fromAddr, hashID3, header.Time.Unix())
+	app.account2UnconfirmedTx.Add(fromAddr, hashID3, 1, header.Time.Unix())
+}
+
+func TestUnconfirmedTxQuota(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	_, _, whitelisted := testutil.KeyPubAddr()
+	acc2unc := NewAccount2UnconfirmedTx(100)
+	acc2unc.SetDefaultQuota(3)
+	acc2unc.SetQuota(whitelisted, UnlimitedQuota)
+
+	for seq := uint64(0); seq < 3; seq++ {
+		hashid := []byte{byte(seq)}
+		require.Equal(t, NoTxExist, acc2unc.Lookup(addr, hashid, 0))
+		acc2unc.Add(addr, hashid, seq, 0)
+		require.Equal(t, SameTxExist, acc2unc.Lookup(addr, hashid, 0))
+	}
+	require.Equal(t, OtherTxExist, acc2unc.Lookup(addr, []byte{3}, 0))
+	for seq := uint64(0); seq < 10; seq++ {
+		acc2unc.Add(whitelisted, []byte{byte(seq)}, seq, 0)
+	}
+	require.Equal(t, NoTxExist, acc2unc.Lookup(whitelisted, []byte{10}, 0))
+
+	// the txs with sequence 0 and 1 are confirmed
+	acc2unc.AddToRemoveList(addr, []byte{1}, 2)
+	acc2unc.CommitRemove(0)
+	require.Equal(t, 1, len(acc2unc.GetTxs(addr)))
+	require.Equal(t, uint64(2), acc2unc.GetTxs(addr)[0].Sequence)
+	require.Equal(t, NoTxExist, acc2unc.Lookup(addr, []byte{3}, 0))
+	acc2unc.ClearRemoveList()
+
+	// a failed tx which did not increase the sequence is removed by its hash
+	acc2unc.AddToRemoveList(addr, []byte{2}, 2)
+	acc2unc.CommitRemove(0)
+	require.Equal(t, 0, len(acc2unc.GetTxs(addr)))
+	acc2unc.ClearRemoveList()
+
+	// expired txs are not counted, and are removed by sweeping
+	acc2unc.SetDefaultQuota(DefaultQuota)
+	acc2unc.Add(addr, []byte{4}, 4, 0)
+	require.Equal(t, OtherTxExist, acc2unc.Lookup(addr, []byte{5}, 100))
+	require.Equal(t, NoTxExist, acc2unc.Lookup(addr, []byte{5}, 101))
+	acc2unc.CommitRemove(101)
+	require.Equal(t, 0, len(acc2unc.GetTxs(addr)))
+	require.Equal(t, 0, len(acc2unc.GetTxs(whitelisted)))
+}
+
+func TestParseQuotaOverrides(t *testing.T) {
+	_, _, addr1 := testutil.KeyPubAddr()
+	_, _, addr2 := testutil.KeyPubAddr()
+	quotas, err := parseQuotaOverrides([]string{addr1.String() + ":5", addr2.String() + ":0"})
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{string(addr1): 5, string(addr2): 0}, quotas)
+
+	_, err = parseQuotaOverrides([]string{addr1.String() + ":-1"})
+	require.NotNil(t, err)
+	_, err = parseQuotaOverrides([]string{"nonsense:1"})
+	require.NotNil(t, err)
+}
+
+func TestUnconfirmedTxExpiry(t *testing.T) {
+	acc2unc := NewAccount2UnconfirmedTx(10)
+	acc2unc.SetMaxExpiredPerCommit(2)
+	addrs := make([]sdk.AccAddress, 5)
+	for i := range addrs {
+		_, _, addrs[i] = testutil.KeyPubAddr()
+		acc2unc.Add(addrs[i], []byte{byte(i)}, 0, int64(i))
+	}
+	// re-added txs and confirmed txs leave no item in the expiry queues
+	acc2unc.Add(addrs[0], []byte{0}, 0, 20)
+	acc2unc.AddToRemoveList(addrs[1], []byte{1}, 1)
+	acc2unc.CommitRemove(5)
+	acc2unc.ClearRemoveList()
+	require.Equal(t, UnconfirmedTxStats{Accounts: 4, Txs: 4, ExpiryQueue: 4, LastSweepTime: 5}, acc2unc.GetStats())
+
+	// the txs added at 2~4 expire at 15, only 2 of them are removed in a commit
+	acc2unc.CommitRemove(15)
+	require.Equal(t, 2, acc2unc.GetStats().ExpiryQueue)
+	acc2unc.CommitRemove(15)
+	stats := acc2unc.GetStats()
+	require.Equal(t, 1, stats.Accounts)
+	require.Equal(t, 1, stats.ExpiryQueue)
+	require.Equal(t, 1, len(acc2unc.GetTxs(addrs[0])))
+
+	acc2unc.CommitRemove(31)
+	require.Equal(t, UnconfirmedTxStats{LastSweepTime: 31}, acc2unc.GetStats())
+}
+
+func TestUnconfirmedTxExpiryQueueBounded(t *testing.T) {
+	acc2unc := NewAccount2UnconfirmedTx(100)
+	acc2unc.SetDefaultQuota(UnlimitedQuota)
+	_, _, addr := testutil.KeyPubAddr()
+	for seq := uint64(0); seq < 1000; seq++ {
+		hashid := []byte{byte(seq), byte(seq >> 8)}
+		acc2unc.Add(addr, hashid, seq, 0)
+		// re-added by recheck, and replaced by another tx with the same sequence
+		acc2unc.Add(addr, hashid, seq, 0)
+		acc2unc.Add(addr, []byte{0xff, byte(seq), byte(seq >> 8)}, seq, 0)
+		acc2unc.Add(addr, []byte{0xfe, byte(seq), byte(seq >> 8)}, seq+1, 0)
+		acc2unc.AddToRemoveList(addr, hashid, seq+1)
+		acc2unc.CommitRemove(0)
+		acc2unc.ClearRemoveList()
+		stats := acc2unc.GetStats()
+		require.Equal(t, 1, stats.Txs)
+		require.Equal(t, 1, stats.ExpiryQueue)
+	}
+}
+
+func TestUnconfirmedTxConcurrency(t *testing.T) {
+	acc2unc := NewAccount2UnconfirmedTx(2)
+	acc2unc.SetDefaultQuota(UnlimitedQuota)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, addr := testutil.KeyPubAddr()
+			for seq := uint64(0); seq < 200; seq++ {
+				hashid := []byte{byte(seq), byte(seq >> 8)}
+				require.Equal(t, NoTxExist, acc2unc.Lookup(addr, hashid, int64(seq)))
+				acc2unc.Add(addr, hashid, seq, int64(seq))
+				acc2unc.AddToRemoveList(addr, hashid, seq)
+				_ = acc2unc.GetTxs(addr)
+			}
+		}()
+	}
+	for timestamp := int64(0); timestamp < 200; timestamp++ {
+		acc2unc.CommitRemove(timestamp)
+		_ = acc2unc.GetStats()
+		acc2unc.SetLimitTime(timestamp%5 + 1)
+	}
+	wg.Wait()
+	acc2unc.CommitRemove(1000)
+	require.Equal(t, 0, acc2unc.GetStats().Txs)
+}
+
+func TestUnconfirmedTxFeeTiers(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	_, _, whitelisted := testutil.KeyPubAddr()
+	feeTiers, err := parseFeeTiers([]string{"100:0", " 10:3"})
+	require.Nil(t, err)
+	require.Equal(t, []FeeTier{{Multiple: sdk.NewDec(10), Quota: 3}, {Multiple: sdk.NewDec(100), Quota: UnlimitedQuota}}, feeTiers)
+	for _, tiers := range [][]string{{"10"}, {"0:1"}, {"x:1"}, {"10:-1"}} {
+		_, err = parseFeeTiers(tiers)
+		require.NotNil(t, err)
+	}
+
+	acc2unc := NewAccount2UnconfirmedTx(100)
+	acc2unc.SetQuota(whitelisted, UnlimitedQuota)
+	acc2unc.SetFeeTiers(feeTiers)
+	require.Equal(t, DefaultQuota, acc2unc.getQuotaForTx(addr, sdk.Dec{}))
+	require.Equal(t, DefaultQuota, acc2unc.getQuotaForTx(addr, sdk.MustNewDecFromStr("9.9")))
+	require.Equal(t, 3, acc2unc.getQuotaForTx(addr, sdk.NewDec(10)))
+	require.Equal(t, UnlimitedQuota, acc2unc.getQuotaForTx(addr, sdk.NewDec(100)))
+	require.Equal(t, UnlimitedQuota, acc2unc.getQuotaForTx(whitelisted, sdk.NewDec(1)))
+	acc2unc.SetQuota(addr, 5)
+	require.Equal(t, 5, acc2unc.getQuotaForTx(addr, sdk.NewDec(10)))
+}
+
+func TestCheckTxWithFeeTiers(t *testing.T) {
+	_, _, toAddr := testutil.KeyPubAddr()
+	key, _, fromAddr := testutil.KeyPubAddr()
+	acc := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	app := initAppWithBaseAccounts(acc)
+	cfg := DefaultUnconfirmedLimitConfig()
+	cfg.FeeTiers = []string{"10:2"}
+	require.Nil(t, app.UpdateUnconfirmedLimitConfig(cfg))
+
+	// check state is ready after the first commit, in which the min gas price is set to 20
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := app.NewContext(false, header)
+	params := app.accountXKeeper.GetParams(ctx)
+	params.MinGasPriceLimit = sdk.NewDec(20)
+	app.accountXKeeper.SetParams(ctx, params)
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	msg := bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(100000000), 0)
+	checkTx := func(seq uint64, fee int64) uint32 {
+		tx := newStdTxBuilder().Msgs(msg).GasAndFee(600000, fee).AccNumSeqKey(0, seq, key).Build()
+		txBytes, _ := auth.DefaultTxEncoder(app.cdc)(tx)
+		return app.CheckTx(abci.RequestCheckTx{Tx: txBytes}).Code
+	}
+	require.Equal(t, uint32(errors.CodeOK), checkTx(0, 12000000))
+	require.Equal(t, uint32(CodeTooManyUnconfirmedTx), checkTx(1, 12000000))
+	require.Equal(t, uint32(CodeTooManyUnconfirmedTx), checkTx(1, 119999999))
+	require.Equal(t, uint32(errors.CodeOK), checkTx(1, 120000000))
+	require.Equal(t, uint32(CodeTooManyUnconfirmedTx), checkTx(2, 120000000))
+}
+
+func TestCheckTxWithInvalidFee(t *testing.T) {
+	_, _, toAddr := testutil.KeyPubAddr()
+	key, _, fromAddr := testutil.KeyPubAddr()
+	acc := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	app := initAppWithBaseAccounts(acc)
+	cfg := DefaultUnconfirmedLimitConfig()
+	cfg.FeeTiers = []string{"10:2"}
+	require.Nil(t, app.UpdateUnconfirmedLimitConfig(cfg))
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	msg := bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(100000000), 0)
+	hugeFee, _ := sdk.NewIntFromString("340282366920938463463374607431768211456") // 2^128
+	for _, fee := range []sdk.Coins{
+		{sdk.NewInt64Coin("cet", 1), sdk.NewInt64Coin("cet", 1)},
+		{sdk.NewInt64Coin("eth", 1), sdk.NewInt64Coin("cet", 1)},
+		{sdk.Coin{Denom: "cet", Amount: sdk.NewInt(-1)}},
+		{sdk.NewCoin("cet", hugeFee)},
+	} {
+		tx := newStdTxBuilder().Msgs(msg).GasAndFee(600000, 12000000).AccNumSeqKey(0, 0, key).Build()
+		tx.Fee.Amount = fee
+		txBytes, _ := auth.DefaultTxEncoder(app.cdc)(tx)
+		var res abci.ResponseCheckTx
+		require.NotPanics(t, func() { res = app.CheckTx(abci.RequestCheckTx{Tx: txBytes}) })
+		require.False(t, res.IsOK())
+	}
+	tx := newStdTxBuilder().Msgs(msg).GasAndFee(600000, 12000000).AccNumSeqKey(0, 0, key).Build()
+	tx.Fee.Gas = math.MaxUint64
+	require.True(t, app.getGasPriceMultiple(app.NewContext(true, abci.Header{}), tx).IsNil())
 }
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// SocketFile is the unix socket under the node's home on which the admin server listens.
+// It is only reachable from the local host, by users who can access the node's home.
+const SocketFile = "data/admin.sock"
+
+// SocketPath returns the admin socket path of the node whose home is rootDir
+func SocketPath(rootDir string) string {
+	return path.Join(rootDir, SocketFile)
+}
+
+// Server serves node-local administrative requests over a unix socket
+type Server struct {
+	router *mux.Router
+	logger log.Logger
+}
+
+func NewServer(logger log.Logger) *Server {
+	return &Server{
+		// the path variables are kept escaped, so that they can contain '/'
+		router: mux.NewRouter().UseEncodedPath(),
+		logger: logger,
+	}
+}
+
+// Router returns the router on which the admin routes are registered
+func (s *Server) Router() *mux.Router {
+	return s.router
+}
+
+// Start listens on sockPath and serves requests in a new goroutine
+func (s *Server) Start(sockPath string) error {
+	if err := os.MkdirAll(path.Dir(sockPath), 0700); err != nil {
+		return err
+	}
+	// the socket file is left behind if the node was not stopped gracefully
+	if err := os.Remove(sockPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(sockPath, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("admin server listens on %s", sockPath))
+	go func() {
+		if err := http.Serve(listener, s.router); err != nil {
+			s.logger.Error(fmt.Sprintf("admin server stopped: %s", err.Error()))
+		}
+	}()
+	return nil
+}
+
+// ReadJSON decodes the json body of the request into obj
+func ReadJSON(r *http.Request, obj interface{}) error {
+	defer r.Body.Close()
+	return json.NewDecoder(r.Body).Decode(obj)
+}
+
+// WriteJSON writes obj as the json body of the response
+func WriteJSON(w http.ResponseWriter, obj interface{}) {
+	bz, err := json.Marshal(obj)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bz)
+}
+
+// WriteError writes err as the body of the response
+func WriteError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(err.Error()))
+}
+
+// Client sends requests to the admin server of a local node
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(sockPath string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", sockPath)
+				},
+			},
+		},
+	}
+}
+
+// Get sends a GET request to route and unmarshals the json response into res
+func (c *Client) Get(route string, res interface{}) error {
+	return c.do(http.MethodGet, route, nil, res)
+}
+
+// Post sends a POST request with body marshaled from req to route,
+// and unmarshals the json response into res if res is not nil
+func (c *Client) Post(route string, req interface{}, res interface{}) error {
+	var body []byte
+	if req != nil {
+		var err error
+		if body, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+	return c.do(http.MethodPost, route, body, res)
+}
+
+func (c *Client) do(method, route string, body []byte, res interface{}) error {
+	// the host is ignored since the connection is always made to the unix socket
+	req, err := http.NewRequest(method, "http://admin"+route, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("admin request %s %s failed: %s", method, route, string(bz))
+	}
+	if res == nil {
+		return nil
+	}
+	return json.Unmarshal(bz, res)
+}
@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
-	"strconv"
 	"sync"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/server"
 	"github.com/spf13/viper"
@@ -134,6 +135,9 @@ type CetChainApp struct {
 	txDecoder sdk.TxDecoder // unmarshal []byte into sdk.Tx
 	txCount   int64
 	height    int64
+	// the records of the node out of the state, such as the last unlock time,
+	// nil if the PubMsg stream is not produced
+	localDB dbm.DB
 
 	invCheckPeriod uint
 
@@ -186,10 +190,38 @@ type CetChainApp struct {
 	currBlockTime          int64
 	account2UnconfirmedTx  *Account2UnconfirmedTx
 
+	unconfirmedLimitMtx           sync.Mutex
+	unconfirmedLimitConfig        UnconfirmedLimitConfig
+	pendingUnconfirmedLimitConfig *UnconfirmedLimitConfig
+
 	// the module manager
 	mm *module.Manager
 
-	pubMsgs []PubMsg
+	// held by Commit and Close
+	commitMtx   sync.Mutex
+	pubMsgs     []PubMsg
+	pubMsgSinks *PubMsgSinks
+	// nil if the outbox is disabled
+	pubMsgOutbox *PubMsgOutbox
+	// the failed_tx_events channel, which never mixes with pubMsgs
+	failedTxEvents     []PubMsg
+	failedTxEventSinks *PubMsgSinks
+	// encodes and sends the msgs of the committed blocks in the background
+	pubMsgQueue *pubMsgQueue
+	// the summary of the current block, nil if the msgqueue feature toggle is off
+	blockSummary *blockSummary
+	// whether the fee of the tx being delivered is charged, set by the ante handler
+	txFeeCharged bool
+	// the vesting accounts not fully vested, and the last block whose unlocks are notified
+	vestingAddrs     []sdk.AccAddress
+	lastUnlockHeight int64
+	lastUnlockTime   time.Time
+
+	// the addresses of pubMsgWatchList, only used by DeliverTx and Commit
+	pubMsgWatched          map[string]bool
+	pubMsgWatchMtx         sync.Mutex
+	pubMsgWatchList        PubMsgWatchList
+	pendingPubMsgWatchList *PubMsgWatchList
 	plugin.Holder
 }
 
@@ -211,15 +243,18 @@ func NewCetChainApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLate
 	app.initKeepers(invCheckPeriod)
 	app.initModules()
 	app.mountStores()
+	app.QueryRouter().AddRoute(UnconfirmedQuerierRoute, app.newUnconfirmedQuerier())
 
 	app.WaitPluginToggleSignal(logger)
+	app.SetCallPolicy(viper.GetDuration(plugin.FlagCallTimeout), viper.GetInt(plugin.FlagMaxFailures))
+	app.SetStateProvider(app.newPluginContext, pluginStateQuerier{app: app})
 
 	ah := authx.NewAnteHandler(app.accountKeeper, app.supplyKeeper, app.accountXKeeper,
 		newAnteHelper(app.accountXKeeper, app.stakingXKeeper))
 
 	app.SetInitChainer(app.initChainer)
 	app.SetBeginBlocker(app.beginBlocker)
-	app.SetAnteHandler(ah)
+	app.SetAnteHandler(app.recordFeeCharged(ah))
 	app.SetEndBlocker(app.endBlocker)
 
 	if loadLatest {
@@ -229,23 +264,12 @@ func NewCetChainApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLate
 		}
 	}
 
-	unconfirmedTxLimitTime, ok := os.LookupEnv("COINEX_UNCONFIRMED_TX_LIMIT_TIME")
-	var limitTime int64
-	var err error
-	if ok {
-		limitTime, err = strconv.ParseInt(unconfirmedTxLimitTime, 10, 64)
-		if err != nil {
-			limitTime = -1
-		}
-	} else {
-		limitTime = DefaultLimitTime
-	}
-	if limitTime > 0 {
-		app.enableUnconfirmedLimit = true
-		app.account2UnconfirmedTx = NewAccount2UnconfirmedTx(limitTime)
-	} else {
-		app.enableUnconfirmedLimit = false
+	cfg := unconfirmedLimitConfigWithEnv(logger)
+	if err := cfg.Validate(); err != nil {
+		cmn.Exit(fmt.Sprintf("invalid unconfirmed limit config: %s", err.Error()))
 	}
+	app.account2UnconfirmedTx = NewAccount2UnconfirmedTx(cfg.LimitTime)
+	app.applyUnconfirmedLimitConfig(cfg)
 	return app
 }
 
@@ -277,7 +301,20 @@ func newCetChainApp(bApp *bam.BaseApp, cdc *codec.Codec, invCheckPeriod uint, tx
 }
 
 func (app *CetChainApp) initMsgQue() {
-	app.msgQueProducer = msgqueue.NewProducer(app.Logger()) // TODO
+	if len(viper.GetStringSlice(msgqueue.FlagBrokers)) == 0 &&
+		(len(getPubMsgSinkConfigs()) != 0 || len(getFailedTxEventSinkConfigs()) != 0) {
+		// the stream is produced for the sinks, even if no broker is configured
+		app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"},
+			viper.GetString(msgqueue.FlagTopics), viper.GetBool(msgqueue.FlagFeatureToggle), app.Logger())
+	} else {
+		app.msgQueProducer = msgqueue.NewProducer(app.Logger()) // TODO
+	}
+	app.initPubMsgSinks()
+	app.initFailedTxEventSinks()
+	app.initPubMsgOutbox()
+	app.initLocalDB()
+	app.initPubMsgQueue()
+	app.initPubMsgWatchList()
 	if isOpenTs() {
 		conf, err := initConf()
 		if err != nil {
@@ -467,7 +504,7 @@ func (app *CetChainApp) initKeepers(invCheckPeriod uint) {
 func (app *CetChainApp) initModules() {
 	modules := app.createAppModules()
 
-	app.mm = module.NewManager(modules...)
+	app.mm = module.NewManager(app.wrapMsgQueueBlockers(modules)...)
 	// During begin block slashing happens after distr.BeginBlocker so that
 	// there is nothing left over in the validator fee pool, so as to keep the
 	// CanWithdrawInvariant invariant.
@@ -566,17 +603,20 @@ func (app *CetChainApp) mountStores() {
 func (app *CetChainApp) beginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
 	app.height = ctx.BlockHeight()
 	app.resetPubMsgBuf()
+	app.blockSummary = nil
 	if app.msgQueProducer.IsOpenToggle() {
 		app.txCount = req.Header.TotalTxs - req.Header.NumTxs
 		app.pushNewHeightInfo(ctx)
+		app.blockSummary = newBlockSummary()
 	}
 	ret := app.mm.BeginBlock(ctx, req)
 	if app.msgQueProducer.IsOpenToggle() {
 		ret.Events = collectKafkaEvents(ret.Events, app)
 		app.notifyBeginBlock(ret.Events)
+		app.blockSummary.feeCollected = app.getFeeCollectorCoins(ctx)
 	}
+	app.currBlockTime = req.Header.Time.Unix()
 	if app.enableUnconfirmedLimit {
-		app.currBlockTime = req.Header.Time.Unix()
 		app.account2UnconfirmedTx.ClearRemoveList()
 	}
 	return ret
@@ -585,10 +625,17 @@ func (app *CetChainApp) beginBlocker(ctx sdk.Context, req abci.RequestBeginBlock
 // application updates every end block
 // nolint: unparam
 func (app *CetChainApp) endBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	var unlocks []NotificationUnlock
+	if app.msgQueProducer.IsOpenToggle() {
+		unlocks = app.getLockedCoinUnlocks(ctx)
+	}
 	ret := app.mm.EndBlock(ctx, req)
 	if app.msgQueProducer.IsOpenToggle() {
 		ret.Events = collectKafkaEvents(ret.Events, app)
-		app.notifyEndBlock(ret.Events)
+		app.notifyEndBlock(ctx, ret.Events)
+		app.notifyValidatorUpdates(ctx, ret.ValidatorUpdates)
+		app.notifyUnlocks(ctx, append(unlocks, app.getVestingUnlocks(ctx)...))
+		app.notifyBlockSummary(ctx)
 	}
 	return ret
 }
@@ -633,17 +680,19 @@ func (app *CetChainApp) appendPubEvent(event abci.Event) {
 		app.appendPubMsg(PubMsg{Key: attr.Key, Value: attr.Value})
 	}
 }
-func (app *CetChainApp) appendPubMsgKV(key string, val []byte) {
-	app.pubMsgs = append(app.pubMsgs, PubMsg{Key: []byte(key), Value: val})
+func (app *CetChainApp) appendPubMsgPayload(key string, payload interface{}) {
+	app.pubMsgs = append(app.pubMsgs, newPubMsg(key, payload))
+}
+func (app *CetChainApp) appendLazyPubMsg(key string, payload func() interface{}) {
+	app.pubMsgs = append(app.pubMsgs, PubMsg{Key: []byte(key), payload: payload})
 }
 
 /* "override" ABCI methods */
 
 func (app *CetChainApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
-	if p := app.GetPlugin(); p != nil {
-		if err := p.PreCheckTx(req, app.txDecoder, app.Logger()); err != nil {
-			return dex.ResponseFrom(err)
-		}
+	if name, err := app.RunPreCheckTx(req, app.txDecoder, app.Logger()); err != nil {
+		app.Logger().Info(fmt.Sprintf("tx rejected by plugin %s: %s", name, err.Error()))
+		return dex.ResponseFrom(err)
 	}
 
 	if !app.enableUnconfirmedLimit {
@@ -672,8 +721,11 @@ func (app *CetChainApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 	otherTxExist := false
 	hashid := tmtypes.Tx(req.Tx).Hash()
 	signers := stdTx.GetSigners()
+	ctx := app.NewContext(true, abci.Header{})
+	sequences := app.getSequences(ctx, signers)
+	multiple := app.getGasPriceMultiple(ctx, stdTx)
 	for _, signer := range signers {
-		res := app.account2UnconfirmedTx.Lookup(signer, hashid, app.currBlockTime)
+		res := app.account2UnconfirmedTx.LookupWithFee(signer, hashid, app.currBlockTime, multiple)
 		if res == OtherTxExist {
 			otherTxExist = true
 			break
@@ -685,13 +737,43 @@ func (app *CetChainApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 	}
 	ret := app.BaseApp.CheckTx(req)
 	if ret.IsOK() {
-		for _, signer := range signers {
-			app.account2UnconfirmedTx.Add(signer, hashid, app.currBlockTime)
+		for i, signer := range signers {
+			app.account2UnconfirmedTx.Add(signer, hashid, sequences[i], app.currBlockTime)
 		}
 	}
 	return ret
 }
 
+func (app *CetChainApp) getSequences(ctx sdk.Context, addrs []sdk.AccAddress) []uint64 {
+	sequences := make([]uint64, len(addrs))
+	for i, addr := range addrs {
+		if acc := app.accountKeeper.GetAccount(ctx, addr); acc != nil {
+			sequences[i] = acc.GetSequence()
+		}
+	}
+	return sequences
+}
+
+// getGasPriceMultiple returns how many times the gas price of tx is of the min gas price, which is the larger
+// one of the node's and the chain's. The result is nil if the min gas price is not positive, or the fee is
+// invalid, which is rejected later by the ante handler. It runs out of the recovery of BaseApp.CheckTx,
+// so StdFee.GasPrices, which panics on the invalid coins, can not be used.
+func (app *CetChainApp) getGasPriceMultiple(ctx sdk.Context, tx auth.StdTx) sdk.Dec {
+	minGasPrice := ctx.MinGasPrices().AmountOf(dex.CET)
+	if limit := app.accountXKeeper.GetParams(ctx).MinGasPriceLimit; limit.GT(minGasPrice) {
+		minGasPrice = limit
+	}
+	if !minGasPrice.IsPositive() || tx.Fee.Gas == 0 || tx.Fee.Gas > math.MaxInt64 || !tx.Fee.Amount.IsValid() {
+		return sdk.Dec{}
+	}
+	fee := tx.Fee.Amount.AmountOf(dex.CET)
+	if fee.BigInt().BitLen() > 128 {
+		// far beyond the supply of CET, and Dec.Quo may overflow
+		return sdk.Dec{}
+	}
+	return sdk.NewDecFromInt(fee).QuoInt64(int64(tx.Fee.Gas)).Quo(minGasPrice)
+}
+
 func (app *CetChainApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDeliverTx {
 	formatOK := true
 	tx, err := app.txDecoder(req.Tx)
@@ -704,35 +786,85 @@ func (app *CetChainApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDelive
 		formatOK = false
 	}
 
+	app.txFeeCharged = false
 	ret := app.BaseApp.DeliverTx(req)
 
 	if app.msgQueProducer.IsOpenToggle() {
+		start := len(app.pubMsgs)
+		app.blockSummary.addTx(stdTx, formatOK, ret)
 		if formatOK {
 			app.notifyTx(req, stdTx, ret)
 		}
 		if ret.Code == uint32(sdk.CodeOK) {
 			ret.Events = collectKafkaEvents(ret.Events, app)
 		} else {
+			app.collectFailedTxEvents(req, ret)
 			ret.Events = discardKafkaEvents(ret.Events)
 		}
+		if !app.isWatchedTx(stdTx, ret.Events) {
+			app.pubMsgs = app.pubMsgs[:start]
+		}
 	}
 
 	if formatOK && app.enableUnconfirmedLimit {
+		hashid := tmtypes.Tx(req.Tx).Hash()
 		signers := stdTx.GetSigners()
-		app.account2UnconfirmedTx.AddToRemoveList(signers)
+		sequences := app.getSequences(app.NewContext(false, abci.Header{}), signers)
+		for i, signer := range signers {
+			app.account2UnconfirmedTx.AddToRemoveList(signer, hashid, sequences[i])
+		}
 	}
+	app.NotifyDeliverTx(req, ret, app.Logger())
+	return ret
+}
+
+func (app *CetChainApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	ret := app.BaseApp.BeginBlock(req)
+	app.NotifyBeginBlock(req, ret, app.Logger())
+	return ret
+}
+
+func (app *CetChainApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	ret := app.BaseApp.EndBlock(req)
+	app.NotifyEndBlock(req, ret, app.Logger())
 	return ret
 }
 
 func (app *CetChainApp) Commit() abci.ResponseCommit {
+	app.commitMtx.Lock()
+	defer app.commitMtx.Unlock()
 	if app.msgQueProducer.IsOpenToggle() {
-		for _, msg := range app.pubMsgs {
-			app.msgQueProducer.SendMsg(msg.Key, msg.Value)
-		}
-		app.msgQueProducer.SendMsg([]byte("commit"), []byte("{}"))
+		app.pushPubMsgBlock()
 	}
 	if app.enableUnconfirmedLimit {
 		app.account2UnconfirmedTx.CommitRemove(app.currBlockTime)
 	}
-	return app.BaseApp.Commit()
+	app.applyPendingUnconfirmedLimitConfig()
+	app.applyPendingPubMsgWatchList()
+	ret := app.BaseApp.Commit()
+	if app.msgQueProducer.IsOpenToggle() {
+		app.saveLastUnlockTime()
+	}
+	app.NotifyCommit(ret, app.Logger())
+	return ret
+}
+
+// Close drains the PubMsg queue, then closes the sinks and the outbox, it is called after the
+// node is stopped. It waits for the Commit in progress, since the consensus does not wait for it when stopped.
+func (app *CetChainApp) Close() {
+	app.commitMtx.Lock()
+	defer app.commitMtx.Unlock()
+	if !app.pubMsgQueue.close() {
+		// the worker still uses the sinks and the outbox
+		app.Logger().Error(fmt.Sprintf("PubMsg queue is not drained in %s, the sinks are left open", sinkCloseTimeout))
+		return
+	}
+	app.pubMsgSinks.Close()
+	app.failedTxEventSinks.Close()
+	if app.pubMsgOutbox != nil {
+		app.pubMsgOutbox.Close()
+	}
+	if app.localDB != nil {
+		app.localDB.Close()
+	}
 }
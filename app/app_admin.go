@@ -0,0 +1,16 @@
+package app
+
+import (
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// StartAdminServer serves the node-local admin API on the unix socket under rootDir
+func (app *CetChainApp) StartAdminServer(rootDir string) error {
+	server := admin.NewServer(app.Logger())
+	app.Holder.RegisterAdminRoutes(server.Router())
+	app.registerUnconfirmedLimitRoutes(server.Router())
+	app.registerPubMsgWatchListRoutes(server.Router())
+	app.registerPubMsgQueueRoutes(server.Router())
+	app.registerPubMsgOutboxRoutes(server.Router())
+	return server.Start(admin.SocketPath(rootDir))
+}
@@ -3,20 +3,19 @@ package app
 import (
 	"encoding/json"
 	"reflect"
-	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
-	cmn "github.com/tendermint/tendermint/libs/common"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	distr "github.com/cosmos/cosmos-sdk/x/distribution"
 	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	gtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	sltypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
-	dex "github.com/coinexchain/cet-sdk/types"
+	"github.com/coinexchain/dex/app/notification"
 )
 
 type TxExtraInfo struct {
@@ -30,56 +29,30 @@ type TxExtraInfo struct {
 	Codespace string       `json:"codespace,omitempty"`
 }
 
-type NewHeightInfo struct {
-	ChainID       string       `json:"chain_id"`
-	Height        int64        `json:"height"`
-	TimeStamp     int64        `json:"timestamp"`
-	LastBlockHash cmn.HexBytes `json:"last_block_hash"`
-}
+type (
+	NewHeightInfo                    = notification.NewHeightInfo
+	TransferRecord                   = notification.TransferRecord
+	NotificationTx                   = notification.NotificationTx
+	NotificationBeginRedelegation    = notification.NotificationBeginRedelegation
+	NotificationBeginUnbonding       = notification.NotificationBeginUnbonding
+	NotificationCompleteRedelegation = notification.NotificationCompleteRedelegation
+	NotificationCompleteUnbonding    = notification.NotificationCompleteUnbonding
+	NotificationSlash                = notification.NotificationSlash
+	NotificationValidatorCommission  = notification.NotificationValidatorCommission
+	NotificationDelegatorRewards     = notification.NotificationDelegatorRewards
+	ValidatorUpdateRecord            = notification.ValidatorUpdateRecord
+	NotificationValidatorSetUpdate   = notification.NotificationValidatorSetUpdate
+)
 
 func (app *CetChainApp) pushNewHeightInfo(ctx sdk.Context) {
 	msg := NewHeightInfo{
+		Version:       HeightInfoVersion,
 		ChainID:       ctx.BlockHeader().ChainID,
 		Height:        ctx.BlockHeight(),
 		TimeStamp:     ctx.BlockHeader().Time.Unix(),
 		LastBlockHash: ctx.BlockHeader().LastBlockId.Hash,
 	}
-	bytes := dex.SafeJSONMarshal(msg)
-	app.appendPubMsgKV("height_info", bytes)
-}
-
-type TransferRecord struct {
-	Sender    string `json:"sender"`
-	Recipient string `json:"recipient"`
-	Amount    string `json:"amount"`
-}
-
-type NotificationTx struct {
-	Signers      []sdk.AccAddress `json:"signers"`
-	Transfers    []TransferRecord `json:"transfers"`
-	SerialNumber int64            `json:"serial_number"`
-	MsgTypes     []string         `json:"msg_types"`
-	TxJSON       string           `json:"tx_json"`
-	Height       int64            `json:"height"`
-	Hash         []byte           `json:"hash"`
-	ExtraInfo    string           `json:"extra_info,omitempty"`
-}
-
-func getTransferRecord(dualEvent []abci.Event) TransferRecord {
-	var res TransferRecord
-	for _, attr := range dualEvent[0].Attributes {
-		if string(attr.Key) == "recipient" {
-			res.Recipient = string(attr.Value)
-		} else if string(attr.Key) == "amount" {
-			res.Amount = string(attr.Value)
-		}
-	}
-	for _, attr := range dualEvent[1].Attributes {
-		if string(attr.Key) == sdk.AttributeKeySender {
-			res.Sender = string(attr.Value)
-		}
-	}
-	return res
+	app.appendPubMsgPayload(KeyHeightInfo, msg)
 }
 
 func getType(myvar interface{}) string {
@@ -90,29 +63,20 @@ func getType(myvar interface{}) string {
 	return t.Name()
 }
 
+// notifyTx decodes the events of a tx inline, while the tx and the notification are marshaled by the PubMsg queue
 func (app *CetChainApp) notifyTx(req abci.RequestDeliverTx, stdTx auth.StdTx, ret abci.ResponseDeliverTx) {
-	events := ret.Events
 	transfers := make([]TransferRecord, 0, 10)
-	ok := ret.Code == uint32(sdk.CodeOK)
-	unbondingMsgList := make([][]byte, 0, 10)
-	redelegationMsgList := make([][]byte, 0, 10)
-	for i := 0; ok && i < len(events); i++ {
-		if events[i].Type == stypes.EventTypeUnbond {
-			if i+1 <= len(events) {
-				val := getNotificationBeginUnbonding(events[i : i+2])
-				unbondingMsgList = append(unbondingMsgList, val)
-				i++
-			}
-		} else if events[i].Type == stypes.EventTypeRedelegate {
-			if i+1 <= len(events) {
-				val := getNotificationBeginRedelegation(events[i : i+2])
-				redelegationMsgList = append(redelegationMsgList, val)
-				i++
+	var unbondings []NotificationBeginUnbonding
+	var redelegations []NotificationBeginRedelegation
+	var msgs []MsgEvents
+	if ret.Code == uint32(sdk.CodeOK) {
+		msgs = DecodeTxEvents(ret.Events)
+		for _, msgEvents := range msgs {
+			for _, moduleEvents := range msgEvents.Modules {
+				transfers = append(transfers, moduleEvents.Transfers()...)
+				unbondings = append(unbondings, moduleEvents.BeginUnbondings()...)
+				redelegations = append(redelegations, moduleEvents.BeginRedelegations()...)
 			}
-		} else if events[i].Type == "transfer" && i+2 <= len(events) {
-			val := getTransferRecord(events[i : i+2])
-			transfers = append(transfers, val)
-			i++
 		}
 	}
 
@@ -125,23 +89,20 @@ func (app *CetChainApp) notifyTx(req abci.RequestDeliverTx, stdTx auth.StdTx, re
 		msgTypes[i] = getType(msg)
 	}
 
-	bytes, errJSON := json.Marshal(&stdTx)
-	if errJSON != nil {
-		return
-	}
-
-	n4s := &NotificationTx{
+	n4s := NotificationTx{
+		Version:      NotifyTxVersion,
 		Signers:      stdTx.GetSigners(),
 		Transfers:    transfers,
 		SerialNumber: app.txCount,
-		TxJSON:       string(bytes),
 		MsgTypes:     msgTypes,
 		Height:       app.height,
 		Hash:         tmtypes.Tx(req.Tx).Hash(),
+		Fee:          getTxFeeInfo(stdTx, ret, app.txFeeCharged, transfers),
 	}
 
+	var txExtraInfo *TxExtraInfo
 	if ret.Code != uint32(sdk.CodeOK) {
-		txExtraInfo := &TxExtraInfo{
+		txExtraInfo = &TxExtraInfo{
 			Code:      ret.Code,
 			Data:      ret.Data,
 			Log:       ret.Log,
@@ -151,93 +112,55 @@ func (app *CetChainApp) notifyTx(req abci.RequestDeliverTx, stdTx auth.StdTx, re
 			Events:    ret.Events,
 			Codespace: ret.Codespace,
 		}
-		bytes, errJSON = json.Marshal(txExtraInfo)
-		if errJSON == nil {
-			n4s.ExtraInfo = string(bytes)
-		}
-	}
-
-	bytes, errJSON = json.Marshal(n4s)
-	if errJSON != nil {
-		return
 	}
 
-	app.appendPubMsgKV("notify_tx", bytes)
-	for _, val := range unbondingMsgList {
-		app.appendPubMsgKV("begin_unbonding", val)
+	app.appendLazyPubMsg(KeyNotifyTx, func() interface{} {
+		return notifyTxPayload{n: n4s, stdTx: stdTx, tx: req.Tx, extraInfo: txExtraInfo}
+	})
+	for _, val := range unbondings {
+		app.appendPubMsgPayload(KeyBeginUnbonding, val)
 	}
-	for _, val := range redelegationMsgList {
-		app.appendPubMsgKV("begin_redelegation", val)
+	for _, val := range redelegations {
+		app.appendPubMsgPayload(KeyBeginRedelegation, val)
 	}
+	app.notifyGovTx(msgs)
 }
 
-type NotificationBeginRedelegation struct {
-	Delegator      string `json:"delegator"`
-	ValidatorSrc   string `json:"src"`
-	ValidatorDst   string `json:"dst"`
-	Amount         string `json:"amount"`
-	CompletionTime int64  `json:"completion_time"`
+// notifyTxPayload is the payload of notify_tx, which carries the tx as JSON,
+// or as the bytes in the block in the binary stream
+type notifyTxPayload struct {
+	n         NotificationTx
+	stdTx     auth.StdTx
+	tx        []byte
+	extraInfo *TxExtraInfo
 }
 
-func getNotificationBeginRedelegation(dualEvent []abci.Event) []byte {
-	var res NotificationBeginRedelegation
-	for _, attr := range dualEvent[0].Attributes {
-		if string(attr.Key) == stypes.AttributeKeySrcValidator {
-			res.ValidatorSrc = string(attr.Value)
-		} else if string(attr.Key) == stypes.AttributeKeyDstValidator {
-			res.ValidatorDst = string(attr.Value)
-		} else if string(attr.Key) == sdk.AttributeKeyAmount {
-			res.Amount = string(attr.Value)
-		} else if string(attr.Key) == stypes.AttributeKeyCompletionTime {
-			if tmp, err := time.Parse(time.RFC3339, string(attr.Value)); err != nil {
-				res.CompletionTime = tmp.Unix()
-			}
-		}
+func (p notifyTxPayload) jsonPayload() interface{} {
+	n := p.withExtraInfo()
+	if bytes, err := json.Marshal(&p.stdTx); err == nil {
+		n.TxJSON = string(bytes)
 	}
-	for _, attr := range dualEvent[1].Attributes {
-		if string(attr.Key) == sdk.AttributeKeySender {
-			res.Delegator = string(attr.Value)
-		}
-	}
-	return dex.SafeJSONMarshal(res)
+	return n
 }
 
-type NotificationBeginUnbonding struct {
-	Delegator      string `json:"delegator"`
-	Validator      string `json:"validator"`
-	Amount         string `json:"amount"`
-	CompletionTime int64  `json:"completion_time"`
+func (p notifyTxPayload) codonPayload() interface{} {
+	n := p.withExtraInfo()
+	n.Tx = p.tx
+	return n
 }
 
-func getNotificationBeginUnbonding(dualEvent []abci.Event) []byte {
-	var res NotificationBeginUnbonding
-	for _, attr := range dualEvent[0].Attributes {
-		if string(attr.Key) == stypes.AttributeKeyValidator {
-			res.Validator = string(attr.Value)
-		} else if string(attr.Key) == sdk.AttributeKeyAmount {
-			res.Amount = string(attr.Value)
-		} else if string(attr.Key) == stypes.AttributeKeyCompletionTime {
-			if tmp, err := time.Parse(time.RFC3339, string(attr.Value)); err != nil {
-				res.CompletionTime = tmp.Unix()
-			}
-		}
-	}
-	for _, attr := range dualEvent[1].Attributes {
-		if string(attr.Key) == sdk.AttributeKeySender {
-			res.Delegator = string(attr.Value)
+func (p notifyTxPayload) withExtraInfo() NotificationTx {
+	n := p.n
+	if p.extraInfo != nil {
+		if bytes, err := json.Marshal(p.extraInfo); err == nil {
+			n.ExtraInfo = string(bytes)
 		}
 	}
-	return dex.SafeJSONMarshal(res)
-}
-
-type NotificationCompleteRedelegation struct {
-	Delegator    string `json:"delegator"`
-	ValidatorSrc string `json:"src"`
-	ValidatorDst string `json:"dst"`
+	return n
 }
 
-func getNotificationCompleteRedelegation(event abci.Event) []byte {
-	var res NotificationCompleteRedelegation
+func getNotificationCompleteRedelegation(event abci.Event) NotificationCompleteRedelegation {
+	res := NotificationCompleteRedelegation{Version: CompleteRedelegationVersion}
 	for _, attr := range event.Attributes {
 		if string(attr.Key) == stypes.AttributeKeyDstValidator {
 			res.ValidatorDst = string(attr.Value)
@@ -247,16 +170,11 @@ func getNotificationCompleteRedelegation(event abci.Event) []byte {
 			res.Delegator = string(attr.Value)
 		}
 	}
-	return dex.SafeJSONMarshal(res)
-}
-
-type NotificationCompleteUnbonding struct {
-	Delegator string `json:"delegator"`
-	Validator string `json:"validator"`
+	return res
 }
 
-func getNotificationCompleteUnbonding(event abci.Event) []byte {
-	var res NotificationCompleteUnbonding
+func getNotificationCompleteUnbonding(event abci.Event) NotificationCompleteUnbonding {
+	res := NotificationCompleteUnbonding{Version: CompleteUnbondingVersion}
 	for _, attr := range event.Attributes {
 		if string(attr.Key) == stypes.AttributeKeyValidator {
 			res.Validator = string(attr.Value)
@@ -264,18 +182,11 @@ func getNotificationCompleteUnbonding(event abci.Event) []byte {
 			res.Delegator = string(attr.Value)
 		}
 	}
-	return dex.SafeJSONMarshal(res)
-}
-
-type NotificationSlash struct {
-	Validator string `json:"validator"`
-	Power     string `json:"power"`
-	Reason    string `json:"reason"`
-	Jailed    bool   `json:"jailed"`
+	return res
 }
 
-func getNotificationSlash(event abci.Event) []byte {
-	var res NotificationSlash
+func getNotificationSlash(event abci.Event) NotificationSlash {
+	res := NotificationSlash{Version: SlashVersion}
 	for _, attr := range event.Attributes {
 		if string(attr.Key) == sltypes.AttributeKeyAddress {
 			res.Validator = string(attr.Value)
@@ -287,7 +198,7 @@ func getNotificationSlash(event abci.Event) []byte {
 			res.Jailed = true
 		}
 	}
-	return dex.SafeJSONMarshal(res)
+	return res
 }
 
 func (app *CetChainApp) notifyBeginBlock(events []abci.Event) {
@@ -298,43 +209,38 @@ func (app *CetChainApp) notifyBeginBlock(events []abci.Event) {
 		//for _, attr := range event.Attributes {
 		//	fmt.Printf("= K: %s; V: %s\n", attr.Key, attr.Value)
 		//}
+		event := event
 		if event.Type == sltypes.EventTypeSlash {
-			val := getNotificationSlash(event)
-			app.appendPubMsgKV("slash", val)
+			app.appendLazyPubMsg(KeySlash, func() interface{} { return getNotificationSlash(event) })
 		} else if subscribedDistr && event.Type == distrtypes.EventTypeCommission {
-			val := getValidatorCommissionMsg(event)
-			app.appendPubMsgKV("validator_commission", val)
+			app.appendLazyPubMsg(KeyValidatorCommission, func() interface{} { return getNotificationValidatorCommission(event) })
 		} else if subscribedDistr && event.Type == distrtypes.EventTypeRewards {
-			val := getDelegatorRewardsMsg(event)
-			app.appendPubMsgKV("delegator_rewards", val)
+			app.appendLazyPubMsg(KeyDelegatorRewards, func() interface{} { return getNotificationDelegatorRewards(event) })
 		}
 	}
 }
 
-func (app *CetChainApp) notifyEndBlock(events []abci.Event) {
+func (app *CetChainApp) notifyEndBlock(ctx sdk.Context, events []abci.Event) {
 	//fmt.Printf("========== EndBlock events ============\n")
 	for _, event := range events {
 		//fmt.Printf("= Event: %s\n", event.Type)
 		//for _, attr := range event.Attributes {
 		//	fmt.Printf("= K: %s; V: %s\n", attr.Key, attr.Value)
 		//}
+		event := event
 		if event.Type == stypes.EventTypeCompleteUnbonding {
-			val := getNotificationCompleteUnbonding(event)
-			app.appendPubMsgKV("complete_unbonding", val)
+			app.appendLazyPubMsg(KeyCompleteUnbonding, func() interface{} { return getNotificationCompleteUnbonding(event) })
 		} else if event.Type == stypes.EventTypeCompleteRedelegation {
-			val := getNotificationCompleteRedelegation(event)
-			app.appendPubMsgKV("complete_redelegation", val)
+			app.appendLazyPubMsg(KeyCompleteRedelegation, func() interface{} { return getNotificationCompleteRedelegation(event) })
+		} else if event.Type == gtypes.EventTypeActiveProposal || event.Type == gtypes.EventTypeInactiveProposal {
+			// the proposal is read from the state inline
+			app.appendPubMsgPayload(KeyProposalResult, app.getNotificationProposalResult(ctx, event))
 		}
 	}
 }
 
-type NotificationValidatorCommission struct {
-	Validator  string `json:"validator"`
-	Commission string `json:"commission"`
-}
-
-func getValidatorCommissionMsg(event abci.Event) []byte {
-	var res NotificationValidatorCommission
+func getNotificationValidatorCommission(event abci.Event) NotificationValidatorCommission {
+	res := NotificationValidatorCommission{Version: ValidatorCommissionVersion}
 	for _, attr := range event.Attributes {
 		if string(attr.Key) == distrtypes.AttributeKeyValidator {
 			res.Validator = string(attr.Value)
@@ -342,16 +248,11 @@ func getValidatorCommissionMsg(event abci.Event) []byte {
 			res.Commission = string(attr.Value)
 		}
 	}
-	return dex.SafeJSONMarshal(res)
-}
-
-type NotificationDelegatorRewards struct {
-	Validator string `json:"validator"`
-	Rewards   string `json:"rewards"`
+	return res
 }
 
-func getDelegatorRewardsMsg(event abci.Event) []byte {
-	var res NotificationDelegatorRewards
+func getNotificationDelegatorRewards(event abci.Event) NotificationDelegatorRewards {
+	res := NotificationDelegatorRewards{Version: DelegatorRewardsVersion}
 	for _, attr := range event.Attributes {
 		if string(attr.Key) == distrtypes.AttributeKeyValidator {
 			res.Validator = string(attr.Value)
@@ -359,5 +260,41 @@ func getDelegatorRewardsMsg(event abci.Event) []byte {
 			res.Rewards = string(attr.Value)
 		}
 	}
-	return dex.SafeJSONMarshal(res)
+	return res
+}
+
+// The reasons of the validators dropped out of the validator set
+const (
+	ValidatorDroppedJailed    = "jailed"
+	ValidatorDroppedUnbonding = "unbonding"
+)
+
+// notifyValidatorUpdates pushes the changes of the validator set returned by EndBlock, whose operators
+// are resolved through the staking keeper. A validator with zero power is dropped out of the set.
+func (app *CetChainApp) notifyValidatorUpdates(ctx sdk.Context, updates []abci.ValidatorUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	res := NotificationValidatorSetUpdate{
+		Version: ValidatorSetUpdateVersion,
+		Updates: make([]ValidatorUpdateRecord, 0, len(updates)),
+	}
+	for _, update := range updates {
+		rec := ValidatorUpdateRecord{Power: update.Power}
+		pubKey, err := tmtypes.PB2TM.PubKey(update.PubKey)
+		if err == nil {
+			rec.ConsensusPubKey, _ = sdk.Bech32ifyConsPub(pubKey)
+			if val, found := app.stakingKeeper.GetValidatorByConsAddr(ctx, sdk.ConsAddress(pubKey.Address())); found {
+				rec.Operator = val.OperatorAddress.String()
+				if update.Power == 0 && val.Jailed {
+					rec.DropReason = ValidatorDroppedJailed
+				}
+			}
+		}
+		if update.Power == 0 && rec.DropReason == "" {
+			rec.DropReason = ValidatorDroppedUnbonding
+		}
+		res.Updates = append(res.Updates, rec)
+	}
+	app.appendPubMsgPayload(KeyValidatorSetUpdate, res)
 }
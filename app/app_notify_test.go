@@ -0,0 +1,68 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+)
+
+func getValidatorSetUpdate(t *testing.T, app *CetChainApp) (res NotificationValidatorSetUpdate) {
+	for _, msg := range app.pubMsgs {
+		if string(msg.Key) == KeyValidatorSetUpdate {
+			require.Nil(t, json.Unmarshal(msg.encoded().Value, &res))
+		}
+	}
+	return
+}
+
+func TestNotifyValidatorSetUpdate(t *testing.T) {
+	valKey, valAcc := testutil.NewBaseAccount(1e9, 0, 0)
+	valAddr := sdk.ValAddress(valAcc.Address)
+	consPubKey, err := sdk.Bech32ifyConsPub(valAcc.PubKey)
+	require.Nil(t, err)
+
+	app := initApp(func(genState *GenesisState) {
+		addGenesisAccounts(genState, valAcc)
+		genState.StakingXData.Params.MinSelfDelegation = 1e8
+	})
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	// the validator joins the set
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	createValMsg := testutil.NewMsgCreateValidatorBuilder(valAddr, valAcc.PubKey).
+		MinSelfDelegation(1e8).SelfDelegation(1e8).
+		Commission("0.1", "0.1", "0.01").
+		Build()
+	createValTx := newStdTxBuilder().
+		Msgs(createValMsg).GasAndFee(1000000, 100).AccNumSeqKey(0, 0, valKey).Build()
+	require.Equal(t, sdk.CodeOK, app.Deliver(createValTx).Code)
+	ret := app.EndBlock(abci.RequestEndBlock{Height: 1})
+	require.Equal(t, 1, len(ret.ValidatorUpdates))
+	require.Equal(t, NotificationValidatorSetUpdate{
+		Version: ValidatorSetUpdateVersion,
+		Updates: []ValidatorUpdateRecord{{ConsensusPubKey: consPubKey, Operator: valAddr.String(), Power: 100}},
+	}, getValidatorSetUpdate(t, app))
+	app.Commit()
+
+	// nothing changes
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	require.Empty(t, getValidatorSetUpdate(t, app).Updates)
+	app.Commit()
+
+	// the validator is jailed and drops out of the set
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 3}})
+	ctx := app.NewContext(false, abci.Header{Height: 3})
+	app.stakingKeeper.Jail(ctx, sdk.ConsAddress(valAcc.PubKey.Address()))
+	app.EndBlock(abci.RequestEndBlock{Height: 3})
+	require.Equal(t, []ValidatorUpdateRecord{{
+		ConsensusPubKey: consPubKey, Operator: valAddr.String(), Power: 0, DropReason: ValidatorDroppedJailed,
+	}}, getValidatorSetUpdate(t, app).Updates)
+}
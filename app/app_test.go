@@ -71,8 +71,11 @@ func newStdTxBuilder() *testutil.StdTxBuilder {
 }
 
 func newApp(baseAppOptions ...func(*bam.BaseApp)) *CetChainApp {
+	return newAppWithDB(dbm.NewMemDB(), baseAppOptions...)
+}
+
+func newAppWithDB(db dbm.DB, baseAppOptions ...func(*bam.BaseApp)) *CetChainApp {
 	logger := log.NewNopLogger()
-	db := dbm.NewMemDB()
 	app := NewCetChainApp(logger, db, nil, true, 10000, baseAppOptions...)
 	topics := "auth,authx,bancorlite,bank,comment,market"
 	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, topics, true, nil)
@@ -125,7 +128,11 @@ func addAccountForDanglingCET(amount int64, genState *GenesisState) {
 }
 
 func initApp(cb genesisStateCallback, baseAppOptions ...func(*bam.BaseApp)) *CetChainApp {
-	app := newApp(baseAppOptions...)
+	return initAppWithDB(dbm.NewMemDB(), cb, baseAppOptions...)
+}
+
+func initAppWithDB(db dbm.DB, cb genesisStateCallback, baseAppOptions ...func(*bam.BaseApp)) *CetChainApp {
+	app := newAppWithDB(db, baseAppOptions...)
 
 	// genesis state
 	genState := NewDefaultGenesisState()
@@ -662,7 +669,7 @@ func TestPubMsgBuf(t *testing.T) {
 	require.Equal(t, 10000, cap(app.pubMsgs))
 	require.Equal(t, 0, len(app.pubMsgs))
 	app.appendPubMsg(PubMsg{Key: []byte("foo"), Value: []byte("bar")})
-	app.appendPubMsgKV("key", []byte("val"))
+	app.appendPubMsgPayload("key", "val")
 	require.Equal(t, 2, len(app.pubMsgs))
 	app.resetPubMsgBuf()
 	require.Equal(t, 10000, cap(app.pubMsgs))
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+)
+
+// FlagFailedTxEventSinks (or the key in app.toml) lists the sinks of the failed_tx_events channel, in the
+// same format as FlagPubMsgSinks. The msgqueue events emitted by a tx before it fails are discarded from
+// the PubMsg stream, and this opt-in channel keeps them for debugging. It is a separate stream which is
+// never sent to the msgqueue brokers, the PubMsg sinks or the outbox, and each height of it also ends
+// with a "commit" msg.
+const FlagFailedTxEventSinks = "failed-tx-event-sinks"
+
+const (
+	KeyFailedTxEvents     = "failed_tx_events"
+	FailedTxEventsVersion = 1
+)
+
+// FailedTxEvent is a msgqueue event emitted by the MsgIndex-th msg of a failed tx
+type FailedTxEvent struct {
+	MsgIndex int    `json:"msg_index"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// NotificationFailedTxEvents is pushed to the failed_tx_events channel for a failed tx which emitted
+// msgqueue events, Hash is the same as the one of its notify_tx
+type NotificationFailedTxEvents struct {
+	Version   int             `json:"version"`
+	Height    int64           `json:"height"`
+	Hash      []byte          `json:"hash"`
+	Code      uint32          `json:"code"`
+	Codespace string          `json:"codespace"`
+	Log       string          `json:"log"`
+	Events    []FailedTxEvent `json:"events"`
+}
+
+func getFailedTxEventSinkConfigs() []string {
+	return viper.GetStringSlice(FlagFailedTxEventSinks)
+}
+
+func (app *CetChainApp) initFailedTxEventSinks() {
+	app.failedTxEventSinks = app.newPubMsgSinks("failed_tx_events", getFailedTxEventSinkConfigs())
+}
+
+// AddFailedTxEventSink lets an embedded consumer receive the failed_tx_events channel, which is produced
+// under the same conditions as the PubMsg stream
+func (app *CetChainApp) AddFailedTxEventSink(sink PubMsgSink, policy SinkPolicy, bufferSize int) error {
+	return app.failedTxEventSinks.Add(sink, policy, bufferSize)
+}
+
+// getFailedTxEvents returns the msgqueue events in the events of a failed tx. The events of a msg
+// are followed by its "message" event with the action attribute.
+func getFailedTxEvents(events []abci.Event) []FailedTxEvent {
+	var res []FailedTxEvent
+	msgIndex := 0
+	for _, event := range events {
+		if event.Type == msgqueue.EventTypeMsgQueue {
+			for _, attr := range event.Attributes {
+				res = append(res, FailedTxEvent{MsgIndex: msgIndex, Key: string(attr.Key), Value: string(attr.Value)})
+			}
+		} else if _, ok := getAttr(event, sdk.AttributeKeyAction); ok && event.Type == sdk.EventTypeMessage {
+			msgIndex++
+		}
+	}
+	return res
+}
+
+// collectFailedTxEvents must be called before the msgqueue events are discarded from ret
+func (app *CetChainApp) collectFailedTxEvents(req abci.RequestDeliverTx, ret abci.ResponseDeliverTx) {
+	if app.failedTxEventSinks.Len() == 0 {
+		return
+	}
+	events := getFailedTxEvents(ret.Events)
+	if len(events) == 0 {
+		return
+	}
+	n := NotificationFailedTxEvents{
+		Version:   FailedTxEventsVersion,
+		Height:    app.height,
+		Hash:      tmtypes.Tx(req.Tx).Hash(),
+		Code:      ret.Code,
+		Codespace: ret.Codespace,
+		Log:       ret.Log,
+		Events:    events,
+	}
+	app.failedTxEvents = append(app.failedTxEvents, newPubMsg(KeyFailedTxEvents, n))
+}
+
+// sendFailedTxEventsToSinks is called by the PubMsg queue with the encoded msgs of a block
+func (app *CetChainApp) sendFailedTxEventsToSinks(height int64, msgs []PubMsg) {
+	if app.failedTxEventSinks.Len() == 0 {
+		return
+	}
+	msgs = append(msgs, PubMsg{Key: []byte("commit"), Value: []byte("{}")})
+	app.failedTxEventSinks.Send(PubMsgBlock{Height: height, Msgs: msgs})
+}
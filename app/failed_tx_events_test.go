@@ -0,0 +1,111 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/cli"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestGetFailedTxEvents(t *testing.T) {
+	events := concatEvents(
+		[]abci.Event{
+			newEvent(msgqueue.EventTypeMsgQueue, "send_lock_coins", "{}"),
+			newEvent("message", "module", "bankx"),
+			newEvent("message", "action", "send"),
+		},
+		[]abci.Event{
+			newEvent(msgqueue.EventTypeMsgQueue, "k1", "v1", "k2", "v2"),
+			newEvent("message", "action", "send"),
+		},
+	)
+	require.Equal(t, []FailedTxEvent{
+		{MsgIndex: 0, Key: "send_lock_coins", Value: "{}"},
+		{MsgIndex: 1, Key: "k1", Value: "v1"},
+		{MsgIndex: 1, Key: "k2", Value: "v2"},
+	}, getFailedTxEvents(events))
+	require.Empty(t, getFailedTxEvents(transferEvents("a", "b", "1cet")))
+}
+
+func TestFailedTxEventsChannel(t *testing.T) {
+	// the keepers decide whether to emit the msgqueue events when they are created
+	viper.Set(msgqueue.FlagBrokers, []string{"nop"})
+	viper.Set(msgqueue.FlagTopics, "bankx")
+	viper.Set(msgqueue.FlagFeatureToggle, true)
+	viper.Set(FlagPubMsgOutboxRetention, 0)
+	// the local db is opened under the home
+	home, err := ioutil.TempDir("", "failed_tx_events")
+	require.Nil(t, err)
+	viper.Set(cli.HomeFlag, home)
+	defer func() {
+		viper.Set(msgqueue.FlagBrokers, nil)
+		viper.Set(msgqueue.FlagTopics, nil)
+		viper.Set(msgqueue.FlagFeatureToggle, nil)
+		viper.Set(FlagPubMsgOutboxRetention, nil)
+		viper.Set(cli.HomeFlag, nil)
+		os.RemoveAll(home)
+	}()
+
+	key, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	acc1 := auth.BaseAccount{Address: toAddr, Coins: dex.NewCetCoins(100)}
+	app := initAppWithBaseAccounts(acc0, acc1)
+	sink, c := NewChanSink("test", 2)
+	require.Nil(t, app.AddFailedTxEventSink(sink, SinkPolicyBlock, 0))
+
+	start := time.Unix(1600000000, 0).UTC()
+	header := abci.Header{Height: 1, Time: start, ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	// the locked send emits a msgqueue event before the second msg fails
+	tx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(500), start.Unix()+10),
+			bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(90000000000), 0)).
+		GasAndFee(1000000, 100).AccNumSeqKey(0, 0, key).Build()
+	ret := app.Deliver(tx)
+	require.NotEqual(t, sdk.CodeOK, ret.Code)
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	for _, msg := range app.pubMsgs {
+		require.NotEqual(t, KeyFailedTxEvents, string(msg.Key))
+		require.NotEqual(t, "send_lock_coins", string(msg.Key))
+	}
+	app.Commit()
+
+	block := <-c
+	require.Equal(t, int64(1), block.Height)
+	require.Len(t, block.Msgs, 2)
+	require.Equal(t, KeyFailedTxEvents, string(block.Msgs[0].Key))
+	require.Equal(t, "commit", string(block.Msgs[1].Key))
+	var n NotificationFailedTxEvents
+	require.Nil(t, json.Unmarshal(block.Msgs[0].Value, &n))
+	require.Equal(t, FailedTxEventsVersion, n.Version)
+	require.Equal(t, int64(1), n.Height)
+	require.Equal(t, uint32(ret.Code), n.Code)
+	require.NotEmpty(t, n.Hash)
+	require.Len(t, n.Events, 1)
+	require.Equal(t, 0, n.Events[0].MsgIndex)
+	require.Equal(t, "send_lock_coins", n.Events[0].Key)
+
+	// a block without failed txs only has "commit"
+	header = abci.Header{Height: 2, Time: start.Add(5 * time.Second), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	app.Commit()
+	block = <-c
+	require.Equal(t, int64(2), block.Height)
+	require.Len(t, block.Msgs, 1)
+}
@@ -0,0 +1,216 @@
+// Package notification defines the payloads of the notifications pushed to the PubMsg stream.
+// It depends on nothing in this repo, so that the codec package can encode them, and the consumers
+// can decode them without importing the app. A struct added or changed here must be registered in
+// codec/types.go and codec/prepare.go, and codec/codec.go must be regenerated.
+package notification
+
+import (
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type NewHeightInfo struct {
+	Version       int          `json:"version"`
+	ChainID       string       `json:"chain_id"`
+	Height        int64        `json:"height"`
+	TimeStamp     int64        `json:"timestamp"`
+	LastBlockHash cmn.HexBytes `json:"last_block_hash"`
+}
+
+type TransferRecord struct {
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Amount    string `json:"amount"`
+}
+
+// NotificationTx carries the tx as JSON in TxJSON. In the binary stream of app/pubmsg, TxJSON is empty,
+// and Tx is the tx as it is in the block, which is a StdTx encoded by the amino codec of the app.
+type NotificationTx struct {
+	Version      int              `json:"version"`
+	Signers      []sdk.AccAddress `json:"signers"`
+	Transfers    []TransferRecord `json:"transfers"`
+	SerialNumber int64            `json:"serial_number"`
+	MsgTypes     []string         `json:"msg_types"`
+	TxJSON       string           `json:"tx_json"`
+	Tx           []byte           `json:"-"`
+	Height       int64            `json:"height"`
+	Hash         []byte           `json:"hash"`
+	ExtraInfo    string           `json:"extra_info,omitempty"`
+	Fee          TxFeeInfo        `json:"fee"`
+}
+
+type NotificationBeginRedelegation struct {
+	Version        int    `json:"version"`
+	Delegator      string `json:"delegator"`
+	ValidatorSrc   string `json:"src"`
+	ValidatorDst   string `json:"dst"`
+	Amount         string `json:"amount"`
+	CompletionTime int64  `json:"completion_time"`
+}
+
+type NotificationBeginUnbonding struct {
+	Version        int    `json:"version"`
+	Delegator      string `json:"delegator"`
+	Validator      string `json:"validator"`
+	Amount         string `json:"amount"`
+	CompletionTime int64  `json:"completion_time"`
+}
+
+type NotificationCompleteRedelegation struct {
+	Version      int    `json:"version"`
+	Delegator    string `json:"delegator"`
+	ValidatorSrc string `json:"src"`
+	ValidatorDst string `json:"dst"`
+}
+
+type NotificationCompleteUnbonding struct {
+	Version   int    `json:"version"`
+	Delegator string `json:"delegator"`
+	Validator string `json:"validator"`
+}
+
+type NotificationSlash struct {
+	Version   int    `json:"version"`
+	Validator string `json:"validator"`
+	Power     string `json:"power"`
+	Reason    string `json:"reason"`
+	Jailed    bool   `json:"jailed"`
+}
+
+type NotificationValidatorCommission struct {
+	Version    int    `json:"version"`
+	Validator  string `json:"validator"`
+	Commission string `json:"commission"`
+}
+
+type NotificationDelegatorRewards struct {
+	Version   int    `json:"version"`
+	Validator string `json:"validator"`
+	Rewards   string `json:"rewards"`
+}
+
+type ValidatorUpdateRecord struct {
+	ConsensusPubKey string `json:"consensus_pubkey"`
+	Operator        string `json:"operator"`
+	Power           int64  `json:"power"`
+	DropReason      string `json:"drop_reason,omitempty"`
+}
+
+type NotificationValidatorSetUpdate struct {
+	Version int                     `json:"version"`
+	Updates []ValidatorUpdateRecord `json:"updates"`
+}
+
+// NotificationSubmitProposal is pushed when a proposal is submitted, and its initial deposit
+// is pushed as a NotificationProposalDeposit
+type NotificationSubmitProposal struct {
+	Version        int    `json:"version"`
+	ProposalID     uint64 `json:"proposal_id"`
+	Proposer       string `json:"proposer"`
+	ProposalType   string `json:"proposal_type"`
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	SubmitTime     int64  `json:"submit_time"`
+	DepositEndTime int64  `json:"deposit_end_time"`
+}
+
+type NotificationProposalDeposit struct {
+	Version    int    `json:"version"`
+	ProposalID uint64 `json:"proposal_id"`
+	Depositor  string `json:"depositor"`
+	Amount     string `json:"amount"`
+}
+
+type NotificationProposalVote struct {
+	Version    int    `json:"version"`
+	ProposalID uint64 `json:"proposal_id"`
+	Voter      string `json:"voter"`
+	Option     string `json:"option"`
+}
+
+// NotificationVotingPeriodStart is pushed when the deposit of a proposal reaches the minimum
+type NotificationVotingPeriodStart struct {
+	Version         int    `json:"version"`
+	ProposalID      uint64 `json:"proposal_id"`
+	TotalDeposit    string `json:"total_deposit"`
+	VotingStartTime int64  `json:"voting_start_time"`
+	VotingEndTime   int64  `json:"voting_end_time"`
+}
+
+type TallyResultRecord struct {
+	Yes        string `json:"yes"`
+	Abstain    string `json:"abstain"`
+	No         string `json:"no"`
+	NoWithVeto string `json:"no_with_veto"`
+}
+
+// NotificationProposalResult is pushed when the voting period of a proposal ends, or its deposit
+// period ends without enough deposit. A dropped proposal has been deleted, so it has no tally result,
+// and HasTallyResult is false.
+type NotificationProposalResult struct {
+	Version        int               `json:"version"`
+	ProposalID     uint64            `json:"proposal_id"`
+	Result         string            `json:"result"`
+	HasTallyResult bool              `json:"has_tally_result"`
+	TallyResult    TallyResultRecord `json:"tally_result"`
+	TotalDeposit   string            `json:"total_deposit,omitempty"`
+}
+
+// NotificationUnlock is pushed for each account and denom whose locked coins of authx or
+// vesting coins become spendable in a block
+type NotificationUnlock struct {
+	Version   int    `json:"version"`
+	Address   string `json:"address"`
+	Denom     string `json:"denom"`
+	Amount    string `json:"amount"`
+	Spendable string `json:"spendable"`
+	Source    string `json:"source"`
+}
+
+type MsgTypeCount struct {
+	MsgType string `json:"msg_type"`
+	Count   int    `json:"count"`
+}
+
+type FeeRecord struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// NotificationBlockSummary is pushed right before "commit" to sum up a block. It counts all the txs,
+// including the ones whose PubMsgs are filtered out by the watch list. Fees are the coins collected by
+// the fee collector in the block, which are the fees of the txs and the ones charged by the modules.
+type NotificationBlockSummary struct {
+	Version         int            `json:"version"`
+	Height          int64          `json:"height"`
+	TxCount         int            `json:"tx_count"`
+	SuccessCount    int            `json:"success_count"`
+	FailureCount    int            `json:"failure_count"`
+	MsgTypeCounts   []MsgTypeCount `json:"msg_type_counts"`
+	GasWanted       int64          `json:"gas_wanted"`
+	GasUsed         int64          `json:"gas_used"`
+	Fees            []FeeRecord    `json:"fees"`
+	MsgQueueModules []string       `json:"msgqueue_modules"`
+}
+
+type GasPriceRecord struct {
+	Denom string `json:"denom"`
+	Price string `json:"price"`
+}
+
+// TxFeeInfo is the fee and gas of a tx. The fee is charged from the payer unless the tx is rejected by the
+// ante handler. EffectiveGasPrice is the charged fee divided by the used gas, for each denom in the fee,
+// including the ones other than CET. The charged fee goes to the fee collector, together with the fees
+// charged by the msgs of a successful tx, while ToCommunityPool is the coins sent to the community pool
+// by the msgs, such as the ones "burned" by the supplyx keeper.
+type TxFeeInfo struct {
+	Payer             string           `json:"payer"`
+	Amount            []FeeRecord      `json:"amount"`
+	Charged           bool             `json:"charged"`
+	GasLimit          uint64           `json:"gas_limit"`
+	GasUsed           int64            `json:"gas_used"`
+	EffectiveGasPrice []GasPriceRecord `json:"effective_gas_price"`
+	ToFeeCollector    []FeeRecord      `json:"to_fee_collector"`
+	ToCommunityPool   []FeeRecord      `json:"to_community_pool"`
+}
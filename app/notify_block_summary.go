@@ -0,0 +1,161 @@
+package app
+
+import (
+	"sort"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/dex/app/notification"
+)
+
+type (
+	MsgTypeCount             = notification.MsgTypeCount
+	FeeRecord                = notification.FeeRecord
+	NotificationBlockSummary = notification.NotificationBlockSummary
+)
+
+// blockSummary collects NotificationBlockSummary from BeginBlock to EndBlock
+type blockSummary struct {
+	txCount         int
+	successCount    int
+	msgTypeCounts   map[string]int
+	gasWanted       int64
+	gasUsed         int64
+	feeCollected    sdk.Coins // by the fee collector before the txs
+	msgQueueModules map[string]bool
+}
+
+func newBlockSummary() *blockSummary {
+	return &blockSummary{
+		msgTypeCounts:   make(map[string]int),
+		msgQueueModules: make(map[string]bool),
+	}
+}
+
+func (app *CetChainApp) getFeeCollectorCoins(ctx sdk.Context) sdk.Coins {
+	return app.supplyKeeper.GetModuleAccount(ctx, auth.FeeCollectorName).GetCoins()
+}
+
+// addTx must be called before the msgqueue events are taken out of ret. The msgqueue events of
+// a msg are taken as emitted by the module which the msg is routed to.
+func (s *blockSummary) addTx(stdTx auth.StdTx, formatOK bool, ret abci.ResponseDeliverTx) {
+	if s == nil {
+		return
+	}
+	s.txCount++
+	s.gasWanted += ret.GasWanted
+	s.gasUsed += ret.GasUsed
+	if !formatOK {
+		return
+	}
+	for _, msg := range stdTx.Msgs {
+		s.msgTypeCounts[getType(msg)]++
+	}
+	if ret.Code != uint32(sdk.CodeOK) {
+		return
+	}
+	s.successCount++
+	for _, msgEvents := range DecodeTxEvents(ret.Events) {
+		if msgEvents.Index >= len(stdTx.Msgs) {
+			break
+		}
+		for _, moduleEvents := range msgEvents.Modules {
+			if hasMsgQueueEvent(moduleEvents.Events) {
+				s.msgQueueModules[stdTx.Msgs[msgEvents.Index].Route()] = true
+			}
+		}
+	}
+}
+
+func hasMsgQueueEvent(events []abci.Event) bool {
+	for _, event := range events {
+		if event.Type == msgqueue.EventTypeMsgQueue {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *blockSummary) toNotification(height int64, fees sdk.Coins) NotificationBlockSummary {
+	res := NotificationBlockSummary{
+		Version:         BlockSummaryVersion,
+		Height:          height,
+		TxCount:         s.txCount,
+		SuccessCount:    s.successCount,
+		FailureCount:    s.txCount - s.successCount,
+		MsgTypeCounts:   make([]MsgTypeCount, 0, len(s.msgTypeCounts)),
+		GasWanted:       s.gasWanted,
+		GasUsed:         s.gasUsed,
+		Fees:            toFeeRecords(fees),
+		MsgQueueModules: make([]string, 0, len(s.msgQueueModules)),
+	}
+	for msgType, count := range s.msgTypeCounts {
+		res.MsgTypeCounts = append(res.MsgTypeCounts, MsgTypeCount{MsgType: msgType, Count: count})
+	}
+	sort.Slice(res.MsgTypeCounts, func(i, j int) bool {
+		return res.MsgTypeCounts[i].MsgType < res.MsgTypeCounts[j].MsgType
+	})
+	for name := range s.msgQueueModules {
+		res.MsgQueueModules = append(res.MsgQueueModules, name)
+	}
+	sort.Strings(res.MsgQueueModules)
+	return res
+}
+
+// notifyBlockSummary must be the last one pushed in EndBlock, so that the summary comes right before "commit"
+func (app *CetChainApp) notifyBlockSummary(ctx sdk.Context) {
+	if app.blockSummary == nil {
+		return
+	}
+	fees, neg := app.getFeeCollectorCoins(ctx).SafeSub(app.blockSummary.feeCollected)
+	if neg {
+		fees = sdk.Coins{}
+	}
+	res := app.blockSummary.toNotification(ctx.BlockHeight(), fees)
+	app.appendPubMsgPayload(KeyBlockSummary, res)
+}
+
+// msgQueueBlocker records the modules which emit msgqueue events in BeginBlock and EndBlock,
+// where the events are not tagged by module as the ones of the txs
+type msgQueueBlocker struct {
+	module.AppModule
+	app *CetChainApp
+}
+
+func (app *CetChainApp) wrapMsgQueueBlockers(modules []module.AppModule) []module.AppModule {
+	res := make([]module.AppModule, len(modules))
+	for i, m := range modules {
+		res[i] = msgQueueBlocker{AppModule: m, app: app}
+	}
+	return res
+}
+
+func (m msgQueueBlocker) BeginBlock(ctx sdk.Context, req abci.RequestBeginBlock) {
+	n := len(ctx.EventManager().Events())
+	m.AppModule.BeginBlock(ctx, req)
+	m.record(ctx.EventManager().Events()[n:])
+}
+
+func (m msgQueueBlocker) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) []abci.ValidatorUpdate {
+	n := len(ctx.EventManager().Events())
+	updates := m.AppModule.EndBlock(ctx, req)
+	m.record(ctx.EventManager().Events()[n:])
+	return updates
+}
+
+func (m msgQueueBlocker) record(events sdk.Events) {
+	if m.app.blockSummary == nil {
+		return
+	}
+	for _, event := range events {
+		if event.Type == msgqueue.EventTypeMsgQueue {
+			m.app.blockSummary.msgQueueModules[m.Name()] = true
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestNotifyBlockSummary(t *testing.T) {
+	key, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	acc1 := auth.BaseAccount{Address: toAddr, Coins: dex.NewCetCoins(100)}
+
+	start := time.Unix(1600000000, 0).UTC()
+	app := initApp(func(genState *GenesisState) {
+		addGenesisAccounts(genState, acc0, acc1)
+	})
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1, Time: start}})
+	tx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(500), 0)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 0, key).Build()
+	ret0 := app.Deliver(tx)
+	require.Equal(t, sdk.CodeOK, ret0.Code)
+	tx = newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(90000000000), 0)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 1, key).Build()
+	ret1 := app.Deliver(tx)
+	require.NotEqual(t, sdk.CodeOK, ret1.Code)
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+
+	last := app.pubMsgs[len(app.pubMsgs)-1]
+	require.Equal(t, KeyBlockSummary, string(last.Key))
+	var summary NotificationBlockSummary
+	require.Nil(t, json.Unmarshal(last.encoded().Value, &summary))
+	require.Equal(t, NotificationBlockSummary{
+		Version:         BlockSummaryVersion,
+		Height:          1,
+		TxCount:         2,
+		SuccessCount:    1,
+		FailureCount:    1,
+		MsgTypeCounts:   []MsgTypeCount{{MsgType: "MsgSend", Count: 2}},
+		GasWanted:       int64(ret0.GasWanted + ret1.GasWanted),
+		GasUsed:         int64(ret0.GasUsed + ret1.GasUsed),
+		Fees:            []FeeRecord{{Denom: dex.CET, Amount: "200"}},
+		MsgQueueModules: []string{},
+	}, summary)
+	app.Commit()
+
+	// an empty block
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2, Time: start.Add(5 * time.Second)}})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	last = app.pubMsgs[len(app.pubMsgs)-1]
+	require.Equal(t, KeyBlockSummary, string(last.Key))
+	summary = NotificationBlockSummary{}
+	require.Nil(t, json.Unmarshal(last.encoded().Value, &summary))
+	require.Equal(t, int64(2), summary.Height)
+	require.Equal(t, 0, summary.TxCount)
+	require.Empty(t, summary.MsgTypeCounts)
+	require.Empty(t, summary.Fees)
+	app.Commit()
+}
+
+func TestBlockSummaryMsgQueueModules(t *testing.T) {
+	_, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	stdTx := auth.StdTx{Msgs: []sdk.Msg{bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(1), 0)}}
+	events := concatEvents(
+		[]abci.Event{newEvent(msgqueue.EventTypeMsgQueue, "send_lock_coins", "{}")},
+		[]abci.Event{
+			newEvent("message", "module", "bankx"),
+			newEvent("message", "action", "send"),
+		},
+	)
+
+	s := newBlockSummary()
+	s.addTx(stdTx, true, abci.ResponseDeliverTx{Code: uint32(sdk.CodeInsufficientCoins), Events: events})
+	require.Empty(t, s.msgQueueModules)
+	s.addTx(stdTx, true, abci.ResponseDeliverTx{Events: events})
+	require.Equal(t, map[string]bool{bankx.RouterKey: true}, s.msgQueueModules)
+	require.Equal(t, 2, s.txCount)
+	require.Equal(t, 1, s.successCount)
+
+	var nilSummary *blockSummary
+	nilSummary.addTx(stdTx, true, abci.ResponseDeliverTx{Events: events})
+
+	app := initApp(nil)
+	app.blockSummary = newBlockSummary()
+	m := msgQueueBlocker{AppModule: app.mm.Modules["staking"], app: app}
+	m.record(sdk.Events{sdk.NewEvent("message")})
+	require.Empty(t, app.blockSummary.msgQueueModules)
+	m.record(sdk.Events{sdk.NewEvent(msgqueue.EventTypeMsgQueue)})
+	require.Equal(t, map[string]bool{"staking": true}, app.blockSummary.msgQueueModules)
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"strconv"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	gtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/coinexchain/dex/app/notification"
+)
+
+// The results of a proposal in NotificationProposalResult
+const (
+	ProposalResultPassed   = "passed"
+	ProposalResultRejected = "rejected"
+	ProposalResultFailed   = "failed"
+	ProposalResultDropped  = "dropped"
+)
+
+var proposalResults = map[string]string{
+	gtypes.AttributeValueProposalPassed:   ProposalResultPassed,
+	gtypes.AttributeValueProposalRejected: ProposalResultRejected,
+	gtypes.AttributeValueProposalFailed:   ProposalResultFailed,
+	gtypes.AttributeValueProposalDropped:  ProposalResultDropped,
+}
+
+type (
+	NotificationSubmitProposal    = notification.NotificationSubmitProposal
+	NotificationProposalDeposit   = notification.NotificationProposalDeposit
+	NotificationProposalVote      = notification.NotificationProposalVote
+	NotificationVotingPeriodStart = notification.NotificationVotingPeriodStart
+	TallyResultRecord             = notification.TallyResultRecord
+	NotificationProposalResult    = notification.NotificationProposalResult
+)
+
+func getProposalID(event abci.Event, key string) (uint64, bool) {
+	s, ok := getAttr(event, key)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(s, 10, 64)
+	return id, err == nil
+}
+
+// ProposalSubmissions returns the proposals submitted by gov, whose proposer is the sender of the msg.
+// Only the proposal IDs and proposers are carried by the events.
+func (m ModuleEvents) ProposalSubmissions() []NotificationSubmitProposal {
+	var res []NotificationSubmitProposal
+	for _, event := range m.Events {
+		if event.Type != gtypes.EventTypeSubmitProposal {
+			continue
+		}
+		// the event emitted for the voting period start has the same type
+		if id, ok := getProposalID(event, gtypes.AttributeKeyProposalID); ok {
+			res = append(res, NotificationSubmitProposal{Version: SubmitProposalVersion, ProposalID: id, Proposer: m.Sender})
+		}
+	}
+	return res
+}
+
+// ProposalDeposits returns the deposits added by gov, whose depositor is the sender of the msg
+func (m ModuleEvents) ProposalDeposits() []NotificationProposalDeposit {
+	var res []NotificationProposalDeposit
+	for _, event := range m.Events {
+		if event.Type != gtypes.EventTypeProposalDeposit {
+			continue
+		}
+		if id, ok := getProposalID(event, gtypes.AttributeKeyProposalID); ok {
+			n := NotificationProposalDeposit{Version: ProposalDepositVersion, ProposalID: id, Depositor: m.Sender}
+			n.Amount, _ = getAttr(event, sdk.AttributeKeyAmount)
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// ProposalVotes returns the votes added by gov, whose voter is the sender of the msg
+func (m ModuleEvents) ProposalVotes() []NotificationProposalVote {
+	var res []NotificationProposalVote
+	for _, event := range m.Events {
+		if event.Type != gtypes.EventTypeProposalVote {
+			continue
+		}
+		if id, ok := getProposalID(event, gtypes.AttributeKeyProposalID); ok {
+			n := NotificationProposalVote{Version: ProposalVoteVersion, ProposalID: id, Voter: m.Sender}
+			n.Option, _ = getAttr(event, gtypes.AttributeKeyOption)
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// VotingPeriodStarts returns the IDs of the proposals entering their voting periods. Gov emits these
+// events after its message event, so they are found in the ModuleEvents without Module.
+func (m ModuleEvents) VotingPeriodStarts() []uint64 {
+	var res []uint64
+	for _, event := range m.Events {
+		if event.Type != gtypes.EventTypeSubmitProposal && event.Type != gtypes.EventTypeProposalDeposit {
+			continue
+		}
+		if id, ok := getProposalID(event, gtypes.AttributeKeyVotingPeriodStart); ok {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// notifyGovTx pushes the gov notifications of a successful tx, and fills the fields not carried by
+// the events with the proposals in the state delivered so far
+func (app *CetChainApp) notifyGovTx(msgs []MsgEvents) {
+	ctx := app.NewContext(false, abci.Header{})
+	for _, msgEvents := range msgs {
+		for _, moduleEvents := range msgEvents.Modules {
+			for _, n := range moduleEvents.ProposalSubmissions() {
+				if proposal, ok := app.govKeeper.GetProposal(ctx, n.ProposalID); ok {
+					n.ProposalType = proposal.ProposalType()
+					n.Title = proposal.GetTitle()
+					n.Description = proposal.GetDescription()
+					n.SubmitTime = proposal.SubmitTime.Unix()
+					n.DepositEndTime = proposal.DepositEndTime.Unix()
+				}
+				app.appendPubMsgPayload(KeySubmitProposal, n)
+			}
+			for _, n := range moduleEvents.ProposalDeposits() {
+				app.appendPubMsgPayload(KeyProposalDeposit, n)
+			}
+			for _, n := range moduleEvents.ProposalVotes() {
+				app.appendPubMsgPayload(KeyProposalVote, n)
+			}
+			for _, id := range moduleEvents.VotingPeriodStarts() {
+				n := NotificationVotingPeriodStart{Version: VotingPeriodStartVersion, ProposalID: id}
+				if proposal, ok := app.govKeeper.GetProposal(ctx, id); ok {
+					n.TotalDeposit = proposal.TotalDeposit.String()
+					n.VotingStartTime = proposal.VotingStartTime.Unix()
+					n.VotingEndTime = proposal.VotingEndTime.Unix()
+				}
+				app.appendPubMsgPayload(KeyVotingPeriodStart, n)
+			}
+		}
+	}
+}
+
+// getNotificationProposalResult decodes the active_proposal and inactive_proposal events of EndBlock.
+// The tally result is stored into the proposal by gov before the event is emitted.
+func (app *CetChainApp) getNotificationProposalResult(ctx sdk.Context, event abci.Event) NotificationProposalResult {
+	res := NotificationProposalResult{Version: ProposalResultVersion}
+	res.ProposalID, _ = getProposalID(event, gtypes.AttributeKeyProposalID)
+	result, _ := getAttr(event, gtypes.AttributeKeyProposalResult)
+	res.Result = proposalResults[result]
+	if event.Type == gtypes.EventTypeActiveProposal {
+		if proposal, ok := app.govKeeper.GetProposal(ctx, res.ProposalID); ok {
+			res.HasTallyResult = true
+			res.TallyResult = newTallyResultRecord(proposal.FinalTallyResult)
+			res.TotalDeposit = proposal.TotalDeposit.String()
+		}
+	}
+	return res
+}
+
+func newTallyResultRecord(tally gov.TallyResult) TallyResultRecord {
+	return TallyResultRecord{
+		Yes:        tally.Yes.String(),
+		Abstain:    tally.Abstain.String(),
+		No:         tally.No.String(),
+		NoWithVeto: tally.NoWithVeto.String(),
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestDecodeGovEvents(t *testing.T) {
+	events := concatEvents(
+		[]abci.Event{newEvent("submit_proposal", "proposal_id", "3")},
+		transferEvents("alice", "gov", "10cet"),
+		[]abci.Event{
+			newEvent("proposal_deposit", "amount", "10cet", "proposal_id", "3"),
+			newEvent("message", "module", "governance", "sender", "alice"),
+			newEvent("submit_proposal", "voting_period_start", "3"),
+			newEvent("message", "action", "submit_proposal"),
+			newEvent("proposal_vote", "option", "Yes", "proposal_id", "2"),
+			newEvent("message", "module", "governance", "sender", "bob"),
+			newEvent("message", "action", "vote"),
+		},
+	)
+	msgs := DecodeTxEvents(events)
+	require.Equal(t, 2, len(msgs))
+	require.Equal(t, 2, len(msgs[0].Modules))
+
+	gov, trailing := msgs[0].Modules[0], msgs[0].Modules[1]
+	require.Equal(t, []NotificationSubmitProposal{{
+		Version: SubmitProposalVersion, ProposalID: 3, Proposer: "alice",
+	}}, gov.ProposalSubmissions())
+	require.Equal(t, []NotificationProposalDeposit{{
+		Version: ProposalDepositVersion, ProposalID: 3, Depositor: "alice", Amount: "10cet",
+	}}, gov.ProposalDeposits())
+	require.Empty(t, gov.VotingPeriodStarts())
+	require.Empty(t, trailing.ProposalSubmissions())
+	require.Equal(t, []uint64{3}, trailing.VotingPeriodStarts())
+
+	require.Equal(t, []NotificationProposalVote{{
+		Version: ProposalVoteVersion, ProposalID: 2, Voter: "bob", Option: "Yes",
+	}}, msgs[1].Modules[0].ProposalVotes())
+}
+
+func getPubMsgs(app *CetChainApp, key string) []map[string]interface{} {
+	var res []map[string]interface{}
+	for _, msg := range app.pubMsgs {
+		if string(msg.Key) == key {
+			var payload map[string]interface{}
+			if json.Unmarshal(msg.encoded().Value, &payload) == nil {
+				res = append(res, payload)
+			}
+		}
+	}
+	return res
+}
+
+func TestNotifyGovLifecycle(t *testing.T) {
+	key, _, addr := testutil.KeyPubAddr()
+	acc := auth.BaseAccount{Address: addr, Coins: dex.NewCetCoins(DefaultGovMinDeposit.Int64() * 2)}
+	deposit := sdk.NewCoins(sdk.NewCoin(dex.CET, DefaultGovMinDeposit))
+	app := initApp(func(genState *GenesisState) {
+		addGenesisAccounts(genState, acc)
+		genState.AuthData = GetDefaultAuthGenesisState()
+		genState.GovData.DepositParams.MinDeposit = deposit
+		genState.GovData.VotingParams.VotingPeriod = VotingPeriod
+	})
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	start := time.Unix(1600000000, 0).UTC()
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1, Time: start}})
+	content := gov.NewTextProposal("title", "description")
+	tx := newStdTxBuilder().
+		Msgs(gov.NewMsgSubmitProposal(content, deposit, addr)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 0, key).Build()
+	require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+	tx = newStdTxBuilder().
+		Msgs(gov.NewMsgVote(addr, 1, gov.OptionYes)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 1, key).Build()
+	require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+
+	submissions := getPubMsgs(app, KeySubmitProposal)
+	require.Equal(t, 1, len(submissions))
+	require.Equal(t, float64(1), submissions[0]["proposal_id"])
+	require.Equal(t, addr.String(), submissions[0]["proposer"])
+	require.Equal(t, "Text", submissions[0]["proposal_type"])
+	require.Equal(t, "title", submissions[0]["title"])
+	require.Equal(t, float64(start.Unix()), submissions[0]["submit_time"])
+
+	deposits := getPubMsgs(app, KeyProposalDeposit)
+	require.Equal(t, 1, len(deposits))
+	require.Equal(t, addr.String(), deposits[0]["depositor"])
+	require.Equal(t, deposit.String(), deposits[0]["amount"])
+
+	starts := getPubMsgs(app, KeyVotingPeriodStart)
+	require.Equal(t, 1, len(starts))
+	require.Equal(t, deposit.String(), starts[0]["total_deposit"])
+	require.Equal(t, float64(start.Add(VotingPeriod).Unix()), starts[0]["voting_end_time"])
+
+	votes := getPubMsgs(app, KeyProposalVote)
+	require.Equal(t, 1, len(votes))
+	require.Equal(t, addr.String(), votes[0]["voter"])
+	require.Equal(t, gov.OptionYes.String(), votes[0]["option"])
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	// no validator has voted, so the quorum is not reached
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2, Time: start.Add(VotingPeriod)}})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	results := getPubMsgs(app, KeyProposalResult)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, ProposalResultRejected, results[0]["result"])
+	require.Equal(t, map[string]interface{}{
+		"yes": "0", "abstain": "0", "no": "0", "no_with_veto": "0",
+	}, results[0]["tally_result"])
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The keys of the notifications pushed by appendPubMsgPayload and appendLazyPubMsg
+const (
+	KeyHeightInfo           = "height_info"
+	KeyNotifyTx             = "notify_tx"
+	KeyBeginUnbonding       = "begin_unbonding"
+	KeyBeginRedelegation    = "begin_redelegation"
+	KeyCompleteUnbonding    = "complete_unbonding"
+	KeyCompleteRedelegation = "complete_redelegation"
+	KeySlash                = "slash"
+	KeyValidatorCommission  = "validator_commission"
+	KeyDelegatorRewards     = "delegator_rewards"
+	KeySubmitProposal       = "submit_proposal"
+	KeyProposalDeposit      = "proposal_deposit"
+	KeyProposalVote         = "proposal_vote"
+	KeyVotingPeriodStart    = "voting_period_start"
+	KeyProposalResult       = "proposal_result"
+	KeyValidatorSetUpdate   = "validator_set_update"
+	KeyUnlock               = "unlock"
+	KeyBlockSummary         = "block_summary"
+)
+
+// The current schema versions of the notifications, which are carried by their "version" fields.
+// Whenever the payload of a key changes, bump its version and freeze the former struct in
+// notify_schema_history.go, so that the schemas of all the versions can still be exported.
+// The current payloads are defined in app/notification.
+// Version 1 of the keys older than the version field is their payloads without it, while the keys
+// added later carry the version field since version 1.
+const (
+	HeightInfoVersion           = 2
+	NotifyTxVersion             = 3
+	BeginUnbondingVersion       = 2
+	BeginRedelegationVersion    = 2
+	CompleteUnbondingVersion    = 2
+	CompleteRedelegationVersion = 2
+	SlashVersion                = 2
+	ValidatorCommissionVersion  = 2
+	DelegatorRewardsVersion     = 2
+	SubmitProposalVersion       = 1
+	ProposalDepositVersion      = 1
+	ProposalVoteVersion         = 1
+	VotingPeriodStartVersion    = 1
+	ProposalResultVersion       = 1
+	ValidatorSetUpdateVersion   = 1
+	UnlockVersion               = 1
+	BlockSummaryVersion         = 1
+)
+
+// NotificationSchema is a version of the payload of a notification key
+type NotificationSchema struct {
+	Key     string
+	Version int
+	Type    reflect.Type
+}
+
+var currentNotificationSchemas = []NotificationSchema{
+	{KeyHeightInfo, HeightInfoVersion, reflect.TypeOf(NewHeightInfo{})},
+	{KeyNotifyTx, NotifyTxVersion, reflect.TypeOf(NotificationTx{})},
+	{KeyBeginUnbonding, BeginUnbondingVersion, reflect.TypeOf(NotificationBeginUnbonding{})},
+	{KeyBeginRedelegation, BeginRedelegationVersion, reflect.TypeOf(NotificationBeginRedelegation{})},
+	{KeyCompleteUnbonding, CompleteUnbondingVersion, reflect.TypeOf(NotificationCompleteUnbonding{})},
+	{KeyCompleteRedelegation, CompleteRedelegationVersion, reflect.TypeOf(NotificationCompleteRedelegation{})},
+	{KeySlash, SlashVersion, reflect.TypeOf(NotificationSlash{})},
+	{KeyValidatorCommission, ValidatorCommissionVersion, reflect.TypeOf(NotificationValidatorCommission{})},
+	{KeyDelegatorRewards, DelegatorRewardsVersion, reflect.TypeOf(NotificationDelegatorRewards{})},
+	{KeySubmitProposal, SubmitProposalVersion, reflect.TypeOf(NotificationSubmitProposal{})},
+	{KeyProposalDeposit, ProposalDepositVersion, reflect.TypeOf(NotificationProposalDeposit{})},
+	{KeyProposalVote, ProposalVoteVersion, reflect.TypeOf(NotificationProposalVote{})},
+	{KeyVotingPeriodStart, VotingPeriodStartVersion, reflect.TypeOf(NotificationVotingPeriodStart{})},
+	{KeyProposalResult, ProposalResultVersion, reflect.TypeOf(NotificationProposalResult{})},
+	{KeyValidatorSetUpdate, ValidatorSetUpdateVersion, reflect.TypeOf(NotificationValidatorSetUpdate{})},
+	{KeyUnlock, UnlockVersion, reflect.TypeOf(NotificationUnlock{})},
+	{KeyBlockSummary, BlockSummaryVersion, reflect.TypeOf(NotificationBlockSummary{})},
+}
+
+// NotificationSchemas returns all the versions of all the keys, sorted by key and then version
+func NotificationSchemas() []NotificationSchema {
+	res := make([]NotificationSchema, 0, len(currentNotificationSchemas)+len(pastNotificationSchemas))
+	res = append(res, currentNotificationSchemas...)
+	res = append(res, pastNotificationSchemas...)
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Key != res[j].Key {
+			return res[i].Key < res[j].Key
+		}
+		return res[i].Version < res[j].Version
+	})
+	return res
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// JSONSchema describes the payload in JSON Schema (draft-07), and pins its "version" field
+func (s NotificationSchema) JSONSchema() map[string]interface{} {
+	res := jsonSchemaOf(s.Type)
+	res["$schema"] = "http://json-schema.org/draft-07/schema#"
+	res["title"] = fmt.Sprintf("%s v%d", s.Key, s.Version)
+	if props, ok := res["properties"].(map[string]interface{}); ok {
+		if _, ok := props["version"]; ok {
+			props["version"] = map[string]interface{}{"type": "integer", "const": s.Version}
+		}
+	}
+	return res
+}
+
+// jsonSchemaOf follows the rules of encoding/json, the types with their own MarshalJSON are taken as strings,
+// such as sdk.AccAddress and cmn.HexBytes
+func jsonSchemaOf(t reflect.Type) map[string]interface{} {
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return map[string]interface{}{"type": "string", "description": t.String()}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonSchemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		// a nil slice is marshalled as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": jsonSchemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchemaOf(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		required := make([]string, 0, t.NumField())
+		addStructFields(t, props, &required)
+		return map[string]interface{}{"type": "object", "properties": props, "required": required}
+	}
+	return map[string]interface{}{}
+}
+
+func addStructFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addStructFields(field.Type, props, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		props[name] = jsonSchemaOf(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
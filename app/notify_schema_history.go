@@ -0,0 +1,103 @@
+package app
+
+import (
+	"reflect"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The structs of the past notification versions, which are only used to export their schemas.
+// They must never be changed.
+var pastNotificationSchemas = []NotificationSchema{
+	{KeyHeightInfo, 1, reflect.TypeOf(heightInfoV1{})},
+	{KeyNotifyTx, 1, reflect.TypeOf(notificationTxV1{})},
+	{KeyNotifyTx, 2, reflect.TypeOf(notificationTxV2{})},
+	{KeyBeginUnbonding, 1, reflect.TypeOf(beginUnbondingV1{})},
+	{KeyBeginRedelegation, 1, reflect.TypeOf(beginRedelegationV1{})},
+	{KeyCompleteUnbonding, 1, reflect.TypeOf(completeUnbondingV1{})},
+	{KeyCompleteRedelegation, 1, reflect.TypeOf(completeRedelegationV1{})},
+	{KeySlash, 1, reflect.TypeOf(slashV1{})},
+	{KeyValidatorCommission, 1, reflect.TypeOf(validatorCommissionV1{})},
+	{KeyDelegatorRewards, 1, reflect.TypeOf(delegatorRewardsV1{})},
+}
+
+type heightInfoV1 struct {
+	ChainID       string       `json:"chain_id"`
+	Height        int64        `json:"height"`
+	TimeStamp     int64        `json:"timestamp"`
+	LastBlockHash cmn.HexBytes `json:"last_block_hash"`
+}
+
+type transferRecordV1 struct {
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Amount    string `json:"amount"`
+}
+
+type notificationTxV1 struct {
+	Signers      []sdk.AccAddress   `json:"signers"`
+	Transfers    []transferRecordV1 `json:"transfers"`
+	SerialNumber int64              `json:"serial_number"`
+	MsgTypes     []string           `json:"msg_types"`
+	TxJSON       string             `json:"tx_json"`
+	Height       int64              `json:"height"`
+	Hash         []byte             `json:"hash"`
+	ExtraInfo    string             `json:"extra_info,omitempty"`
+}
+
+type notificationTxV2 struct {
+	Version      int                `json:"version"`
+	Signers      []sdk.AccAddress   `json:"signers"`
+	Transfers    []transferRecordV1 `json:"transfers"`
+	SerialNumber int64              `json:"serial_number"`
+	MsgTypes     []string           `json:"msg_types"`
+	TxJSON       string             `json:"tx_json"`
+	Height       int64              `json:"height"`
+	Hash         []byte             `json:"hash"`
+	ExtraInfo    string             `json:"extra_info,omitempty"`
+}
+
+type beginRedelegationV1 struct {
+	Delegator      string `json:"delegator"`
+	ValidatorSrc   string `json:"src"`
+	ValidatorDst   string `json:"dst"`
+	Amount         string `json:"amount"`
+	CompletionTime int64  `json:"completion_time"`
+}
+
+type beginUnbondingV1 struct {
+	Delegator      string `json:"delegator"`
+	Validator      string `json:"validator"`
+	Amount         string `json:"amount"`
+	CompletionTime int64  `json:"completion_time"`
+}
+
+type completeRedelegationV1 struct {
+	Delegator    string `json:"delegator"`
+	ValidatorSrc string `json:"src"`
+	ValidatorDst string `json:"dst"`
+}
+
+type completeUnbondingV1 struct {
+	Delegator string `json:"delegator"`
+	Validator string `json:"validator"`
+}
+
+type slashV1 struct {
+	Validator string `json:"validator"`
+	Power     string `json:"power"`
+	Reason    string `json:"reason"`
+	Jailed    bool   `json:"jailed"`
+}
+
+type validatorCommissionV1 struct {
+	Validator  string `json:"validator"`
+	Commission string `json:"commission"`
+}
+
+type delegatorRewardsV1 struct {
+	Validator string `json:"validator"`
+	Rewards   string `json:"rewards"`
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sltypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestNotificationSchemaVersions(t *testing.T) {
+	versions := make(map[string][]int)
+	for _, s := range NotificationSchemas() {
+		versions[s.Key] = append(versions[s.Key], s.Version)
+	}
+	for _, s := range currentNotificationSchemas {
+		// all the versions are kept, and the current one is the latest
+		for i, v := range versions[s.Key] {
+			require.Equal(t, i+1, v, s.Key)
+		}
+		require.Equal(t, s.Version, len(versions[s.Key]), s.Key)
+
+		field, ok := s.Type.FieldByName("Version")
+		require.True(t, ok, s.Key)
+		require.Equal(t, `json:"version"`, string(field.Tag))
+	}
+}
+
+func TestNotificationJSONSchema(t *testing.T) {
+	for _, s := range NotificationSchemas() {
+		schema := s.JSONSchema()
+		require.Equal(t, "object", schema["type"])
+		props := schema["properties"].(map[string]interface{})
+
+		// the fields of a marshalled payload are the properties in the schema
+		bz, err := json.Marshal(reflect.New(s.Type).Interface())
+		require.Nil(t, err)
+		var payload map[string]interface{}
+		require.Nil(t, json.Unmarshal(bz, &payload))
+		for name := range payload {
+			require.Contains(t, props, name, s.Key)
+		}
+		for _, name := range schema["required"].([]string) {
+			require.Contains(t, payload, name, s.Key)
+		}
+		if _, ok := s.Type.FieldByName("Version"); ok {
+			require.Equal(t, s.Version, props["version"].(map[string]interface{})["const"])
+		} else {
+			require.NotContains(t, props, "version")
+		}
+	}
+
+	schema := jsonSchemaOf(reflect.TypeOf(TxExtraInfo{}))
+	props := schema["properties"].(map[string]interface{})
+	require.Empty(t, schema["required"])
+	require.Equal(t, "base64", props["data"].(map[string]interface{})["contentEncoding"])
+	events := props["events"].(map[string]interface{})["items"].(map[string]interface{})
+	require.NotContains(t, events["properties"], "XXX_unrecognized")
+}
+
+func TestNotificationVersionInPayload(t *testing.T) {
+	event := abci.Event{
+		Type: sltypes.EventTypeSlash,
+		Attributes: []cmn.KVPair{
+			{Key: []byte(sltypes.AttributeKeyAddress), Value: []byte("val")},
+			{Key: []byte(sltypes.AttributeKeyJailed), Value: []byte("val")},
+		},
+	}
+	var slash NotificationSlash
+	require.Nil(t, json.Unmarshal(dex.SafeJSONMarshal(getNotificationSlash(event)), &slash))
+	require.Equal(t, NotificationSlash{Version: SlashVersion, Validator: "val", Jailed: true}, slash)
+
+	var rewards map[string]interface{}
+	require.Nil(t, json.Unmarshal(dex.SafeJSONMarshal(getNotificationDelegatorRewards(abci.Event{})), &rewards))
+	require.Equal(t, float64(DelegatorRewardsVersion), rewards["version"])
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	distr "github.com/cosmos/cosmos-sdk/x/distribution"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+
+	"github.com/coinexchain/dex/app/notification"
+)
+
+type (
+	GasPriceRecord = notification.GasPriceRecord
+	TxFeeInfo      = notification.TxFeeInfo
+)
+
+var (
+	feeCollectorAddr  = supply.NewModuleAddress(auth.FeeCollectorName).String()
+	communityPoolAddr = supply.NewModuleAddress(distr.ModuleName).String()
+)
+
+// recordFeeCharged wraps the ante handler to record whether the tx being delivered is charged its fee,
+// which is written to the store unless the ante handler aborts. The result of DeliverTx can not tell it,
+// because the ante handler and the msgs may both run out of gas.
+func (app *CetChainApp) recordFeeCharged(ah sdk.AnteHandler) sdk.AnteHandler {
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		newCtx, res, abort := ah(ctx, tx, simulate)
+		if !ctx.IsCheckTx() && !simulate {
+			app.txFeeCharged = !abort
+		}
+		return newCtx, res, abort
+	}
+}
+
+// getTxFeeInfo splits the fees by the transfers decoded from the events of a successful tx
+func getTxFeeInfo(stdTx auth.StdTx, ret abci.ResponseDeliverTx, charged bool, transfers []TransferRecord) TxFeeInfo {
+	res := TxFeeInfo{
+		Amount:            toFeeRecords(stdTx.Fee.Amount),
+		Charged:           charged,
+		GasLimit:          stdTx.Fee.Gas,
+		GasUsed:           ret.GasUsed,
+		EffectiveGasPrice: []GasPriceRecord{},
+	}
+	if signers := stdTx.GetSigners(); len(signers) != 0 {
+		res.Payer = signers[0].String()
+	}
+	var toFeeCollector, toCommunityPool sdk.Coins
+	if charged {
+		toFeeCollector = stdTx.Fee.Amount
+		if ret.GasUsed > 0 {
+			for _, coin := range stdTx.Fee.Amount {
+				price := sdk.NewDecFromInt(coin.Amount).QuoInt64(ret.GasUsed)
+				res.EffectiveGasPrice = append(res.EffectiveGasPrice, GasPriceRecord{Denom: coin.Denom, Price: price.String()})
+			}
+		}
+	}
+	for _, t := range transfers {
+		if t.Recipient != feeCollectorAddr && t.Recipient != communityPoolAddr {
+			continue
+		}
+		amount, err := sdk.ParseCoins(t.Amount)
+		if err != nil {
+			continue
+		}
+		if t.Recipient == feeCollectorAddr {
+			toFeeCollector = toFeeCollector.Add(amount)
+		} else {
+			toCommunityPool = toCommunityPool.Add(amount)
+		}
+	}
+	res.ToFeeCollector = toFeeRecords(toFeeCollector)
+	res.ToCommunityPool = toFeeRecords(toCommunityPool)
+	return res
+}
+
+func toFeeRecords(coins sdk.Coins) []FeeRecord {
+	res := make([]FeeRecord, 0, len(coins))
+	for _, coin := range coins {
+		res = append(res, FeeRecord{Denom: coin.Denom, Amount: coin.Amount.String()})
+	}
+	return res
+}
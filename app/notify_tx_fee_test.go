@@ -0,0 +1,99 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func getNotifyTxs(t *testing.T, app *CetChainApp) []NotificationTx {
+	var res []NotificationTx
+	for _, msg := range app.pubMsgs {
+		if string(msg.Key) == KeyNotifyTx {
+			var n NotificationTx
+			require.Nil(t, json.Unmarshal(msg.encoded().Value, &n))
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func TestNotifyTxFee(t *testing.T) {
+	key, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	acc1 := auth.BaseAccount{Address: toAddr, Coins: dex.NewCetCoins(100)}
+	app := initAppWithBaseAccounts(acc0, acc1)
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	header := abci.Header{Height: 1, Time: time.Unix(1600000000, 0), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	okTx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(500), 0)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 0, key).Build()
+	ret0 := app.Deliver(okTx)
+	require.Equal(t, sdk.CodeOK, ret0.Code)
+	failedTx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(90000000000), 0)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 1, key).Build()
+	ret1 := app.Deliver(failedTx)
+	require.NotEqual(t, sdk.CodeOK, ret1.Code)
+	// rejected by the ante handler for the reused sequence
+	rejectedTx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(500), 0)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 1, key).Build()
+	require.NotEqual(t, sdk.CodeOK, app.Deliver(rejectedTx).Code)
+
+	txs := getNotifyTxs(t, app)
+	require.Len(t, txs, 3)
+	fee := []FeeRecord{{Denom: dex.CET, Amount: "100"}}
+	for i, ret := range []sdk.Result{ret0, ret1} {
+		price := sdk.NewDec(100).QuoInt64(int64(ret.GasUsed)).String()
+		require.Equal(t, TxFeeInfo{
+			Payer:             fromAddr.String(),
+			Amount:            fee,
+			Charged:           true,
+			GasLimit:          1000000,
+			GasUsed:           int64(ret.GasUsed),
+			EffectiveGasPrice: []GasPriceRecord{{Denom: dex.CET, Price: price}},
+			ToFeeCollector:    fee,
+			ToCommunityPool:   []FeeRecord{},
+		}, txs[i].Fee)
+	}
+	require.False(t, txs[2].Fee.Charged)
+	require.Equal(t, fee, txs[2].Fee.Amount)
+	require.Empty(t, txs[2].Fee.EffectiveGasPrice)
+	require.Empty(t, txs[2].Fee.ToFeeCollector)
+}
+
+func TestGetTxFeeInfoSplit(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	stdTx := auth.StdTx{
+		Msgs: []sdk.Msg{bankx.NewMsgSend(addr, addr, dex.NewCetCoins(1), 0)},
+		Fee:  auth.NewStdFee(2000, dex.NewCetCoins(200)),
+	}
+	transfers := []TransferRecord{
+		{Sender: addr.String(), Recipient: addr.String(), Amount: "1cet"},
+		{Sender: addr.String(), Recipient: feeCollectorAddr, Amount: "10cet"},
+		{Sender: addr.String(), Recipient: communityPoolAddr, Amount: "5cet,3abc"},
+	}
+	info := getTxFeeInfo(stdTx, abci.ResponseDeliverTx{GasUsed: 1000}, true, transfers)
+	require.Equal(t, []GasPriceRecord{{Denom: dex.CET, Price: "0.200000000000000000"}}, info.EffectiveGasPrice)
+	require.Equal(t, []FeeRecord{{Denom: dex.CET, Amount: "210"}}, info.ToFeeCollector)
+	require.Equal(t, []FeeRecord{{Denom: "abc", Amount: "3"}, {Denom: dex.CET, Amount: "5"}}, info.ToCommunityPool)
+
+	info = getTxFeeInfo(stdTx, abci.ResponseDeliverTx{GasUsed: 1000}, false, nil)
+	require.Empty(t, info.EffectiveGasPrice)
+	require.Empty(t, info.ToFeeCollector)
+}
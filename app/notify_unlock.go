@@ -0,0 +1,179 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/dex/app/notification"
+)
+
+// The sources of the coins in NotificationUnlock
+const (
+	UnlockSourceLockedCoin = "locked_coin"
+	UnlockSourceVesting    = "vesting"
+)
+
+type NotificationUnlock = notification.NotificationUnlock
+
+// getLockedCoinUnlocks returns the locked coins to be unlocked by the EndBlocker of authx,
+// so it must be called before that
+func (app *CetChainApp) getLockedCoinUnlocks(ctx sdk.Context) []NotificationUnlock {
+	now := ctx.BlockHeader().Time.Unix()
+	var addrs []sdk.AccAddress
+	seen := make(map[string]bool)
+	iterator := app.accountXKeeper.UnlockedCoinsQueueIterator(ctx, now)
+	for ; iterator.Valid(); iterator.Next() {
+		addr := sdk.AccAddress(iterator.Value())
+		if len(addr) != 0 && !seen[string(addr)] {
+			seen[string(addr)] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	iterator.Close()
+
+	var res []NotificationUnlock
+	for _, addr := range addrs {
+		accx, ok := app.accountXKeeper.GetAccountX(ctx, addr)
+		if !ok {
+			continue
+		}
+		unlocked := sdk.Coins{}
+		for _, c := range accx.LockedCoins {
+			if c.UnlockTime <= now {
+				unlocked = unlocked.Add(sdk.Coins{c.Coin})
+			}
+		}
+		res = appendUnlocks(res, addr, unlocked.Sort(), UnlockSourceLockedCoin)
+	}
+	return res
+}
+
+// LocalDBName is the LevelDB beside the outbox, which keeps the records of the node out of the state
+const LocalDBName = "pubmsg-local"
+
+// FlagLocalDBDir (or the key in app.toml) is the dir of the local db, which is the data dir under home
+// by default. It is set by the commands which run the app on a copy of the state, such as backfill-msgs.
+const FlagLocalDBDir = "pubmsg-local-db-dir"
+
+// lastUnlockTimeKey is the key in the local db to record the last block whose unlocks are notified,
+// so that the unlocks since it are not lost after restarting
+var lastUnlockTimeKey = []byte("last-unlock-time")
+
+// initLocalDB opens the local db if the PubMsg stream is produced
+func (app *CetChainApp) initLocalDB() {
+	if !app.msgQueProducer.IsOpenToggle() {
+		return
+	}
+	dir := viper.GetString(FlagLocalDBDir)
+	if dir == "" {
+		dir = PubMsgOutboxDir(viper.GetString(cli.HomeFlag))
+	}
+	app.localDB = dbm.NewDB(LocalDBName, dbm.GoLevelDBBackend, dir)
+}
+
+type lastUnlockRecord struct {
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+}
+
+// saveLastUnlockTime is called after the stores are committed. If the node is killed before it, the
+// unlocks of the committed block are notified again by the next block after restarting.
+func (app *CetChainApp) saveLastUnlockTime() {
+	if app.localDB == nil || app.lastUnlockTime.IsZero() {
+		return
+	}
+	bz, err := json.Marshal(lastUnlockRecord{Height: app.lastUnlockHeight, Time: app.lastUnlockTime})
+	if err != nil {
+		app.Logger().Error(fmt.Sprintf("marshal the last unlock time failed: %s", err.Error()))
+		return
+	}
+	app.localDB.Set(lastUnlockTimeKey, bz)
+}
+
+// loadLastUnlockTime returns the time saved by the blocks before height, if any
+func (app *CetChainApp) loadLastUnlockTime(height int64) time.Time {
+	if app.localDB == nil {
+		return time.Time{}
+	}
+	var record lastUnlockRecord
+	bz := app.localDB.Get(lastUnlockTimeKey)
+	if bz == nil || json.Unmarshal(bz, &record) != nil || record.Height >= height {
+		return time.Time{}
+	}
+	return record.Time
+}
+
+// getVestingUnlocks returns the coins vested since the last block. The vesting accounts can only be
+// created in genesis, so they are loaded once, and the ones fully vested are left out afterwards.
+// Nothing is returned for the first block of the chain, or the first one after starting if the
+// last block time is not saved, such as when the msgqueue feature toggle was off.
+func (app *CetChainApp) getVestingUnlocks(ctx sdk.Context) []NotificationUnlock {
+	now := ctx.BlockHeader().Time
+	last := app.lastUnlockTime
+	if last.IsZero() {
+		app.vestingAddrs = app.stakingXKeeper.GetAllVestingAccountAddresses(ctx)
+		last = app.loadLastUnlockTime(ctx.BlockHeight())
+	}
+	app.lastUnlockHeight = ctx.BlockHeight()
+	app.lastUnlockTime = now
+	if last.IsZero() {
+		return nil
+	}
+
+	var res []NotificationUnlock
+	remaining := app.vestingAddrs[:0]
+	for _, addr := range app.vestingAddrs {
+		vacc, ok := app.accountKeeper.GetAccount(ctx, addr).(auth.VestingAccount)
+		if !ok {
+			continue
+		}
+		if vacc.GetEndTime() > now.Unix() {
+			remaining = append(remaining, addr)
+		}
+		vested, neg := vacc.GetVestedCoins(now).SafeSub(vacc.GetVestedCoins(last))
+		if !neg {
+			res = appendUnlocks(res, addr, vested, UnlockSourceVesting)
+		}
+	}
+	app.vestingAddrs = remaining
+	return res
+}
+
+func appendUnlocks(unlocks []NotificationUnlock, addr sdk.AccAddress, coins sdk.Coins, source string) []NotificationUnlock {
+	for _, coin := range coins {
+		if !coin.IsPositive() {
+			continue
+		}
+		unlocks = append(unlocks, NotificationUnlock{
+			Version: UnlockVersion,
+			Address: addr.String(),
+			Denom:   coin.Denom,
+			Amount:  coin.Amount.String(),
+			Source:  source,
+		})
+	}
+	return unlocks
+}
+
+// notifyUnlocks pushes the unlocks with the spendable balances after EndBlock
+func (app *CetChainApp) notifyUnlocks(ctx sdk.Context, unlocks []NotificationUnlock) {
+	now := ctx.BlockHeader().Time
+	for _, n := range unlocks {
+		addr, err := sdk.AccAddressFromBech32(n.Address)
+		if err != nil {
+			continue
+		}
+		if acc := app.accountKeeper.GetAccount(ctx, addr); acc != nil {
+			n.Spendable = acc.SpendableCoins(now).AmountOf(n.Denom).String()
+		}
+		app.appendPubMsgPayload(KeyUnlock, n)
+	}
+}
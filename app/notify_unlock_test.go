@@ -0,0 +1,140 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func getUnlocks(t *testing.T, app *CetChainApp) []NotificationUnlock {
+	var res []NotificationUnlock
+	for _, msg := range app.pubMsgs {
+		if string(msg.Key) == KeyUnlock {
+			var n NotificationUnlock
+			require.Nil(t, json.Unmarshal(msg.encoded().Value, &n))
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func TestNotifyUnlocks(t *testing.T) {
+	key, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	_, _, vestingAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	acc1 := auth.BaseAccount{Address: toAddr, Coins: dex.NewCetCoins(100)}
+	acc2 := auth.BaseAccount{Address: vestingAddr, Coins: dex.NewCetCoins(1000)}
+
+	start := time.Unix(1600000000, 0).UTC()
+	app := initApp(func(genState *GenesisState) {
+		addGenesisAccounts(genState, acc0, acc1, acc2)
+		genState.AuthData = GetDefaultAuthGenesisState()
+		vacc := &genState.Accounts[2]
+		vacc.OriginalVesting = dex.NewCetCoins(600)
+		vacc.EndTime = start.Unix() + 20
+	})
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	// the first block only loads the vesting accounts
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1, Time: start}})
+	coins := dex.NewCetCoins(500)
+	tx := newStdTxBuilder().
+		Msgs(bankx.NewMsgSend(fromAddr, toAddr, coins, start.Unix()+10)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 0, key).Build()
+	require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	require.Empty(t, getUnlocks(t, app))
+	app.Commit()
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2, Time: start.Add(10 * time.Second)}})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	require.Equal(t, []NotificationUnlock{{
+		Version: UnlockVersion, Address: toAddr.String(), Denom: dex.CET,
+		Amount: "500", Spendable: "600", Source: UnlockSourceLockedCoin,
+	}}, getUnlocks(t, app))
+	app.Commit()
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 3, Time: start.Add(20 * time.Second)}})
+	app.EndBlock(abci.RequestEndBlock{Height: 3})
+	require.Equal(t, []NotificationUnlock{{
+		Version: UnlockVersion, Address: vestingAddr.String(), Denom: dex.CET,
+		Amount: "600", Spendable: "1000", Source: UnlockSourceVesting,
+	}}, getUnlocks(t, app))
+	require.Empty(t, app.vestingAddrs)
+	app.Commit()
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 4, Time: start.Add(30 * time.Second)}})
+	app.EndBlock(abci.RequestEndBlock{Height: 4})
+	require.Empty(t, getUnlocks(t, app))
+}
+
+func TestNotifyVestingUnlocksAfterRestart(t *testing.T) {
+	_, _, vestingAddr := testutil.KeyPubAddr()
+	acc := auth.BaseAccount{Address: vestingAddr, Coins: dex.NewCetCoins(1000)}
+
+	start := time.Unix(1600000000, 0).UTC()
+	db, localDB := dbm.NewMemDB(), dbm.NewMemDB()
+	app := initAppWithDB(db, func(genState *GenesisState) {
+		addGenesisAccounts(genState, acc)
+		genState.AuthData = GetDefaultAuthGenesisState()
+		vacc := &genState.Accounts[0]
+		vacc.OriginalVesting = dex.NewCetCoins(600)
+		vacc.StartTime = start.Unix()
+		vacc.EndTime = start.Unix() + 100
+	})
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.localDB = localDB
+	execBlock := func(app *CetChainApp, height int64, seconds int) []NotificationUnlock {
+		header := abci.Header{Height: height, Time: start.Add(time.Duration(seconds) * time.Second)}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{Height: height})
+		unlocks := getUnlocks(t, app)
+		app.Commit()
+		return unlocks
+	}
+	vested := func(amount string) []NotificationUnlock {
+		return []NotificationUnlock{{
+			Version: UnlockVersion, Address: vestingAddr.String(), Denom: dex.CET,
+			Amount: amount, Spendable: "", Source: UnlockSourceVesting,
+		}}
+	}
+	withoutSpendable := func(unlocks []NotificationUnlock) []NotificationUnlock {
+		for i := range unlocks {
+			unlocks[i].Spendable = ""
+		}
+		return unlocks
+	}
+
+	require.Empty(t, execBlock(app, 1, 0))
+	require.Equal(t, vested("60"), withoutSpendable(execBlock(app, 2, 10)))
+
+	// the coins vested between the last block before restarting and the first one after it are notified
+	app = NewCetChainApp(log.NewNopLogger(), db, nil, true, 10000)
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.localDB = localDB
+	require.Equal(t, vested("120"), withoutSpendable(execBlock(app, 3, 30)))
+	require.Equal(t, vested("60"), withoutSpendable(execBlock(app, 4, 40)))
+
+	// a saved time which is not before the block is ignored
+	app = NewCetChainApp(log.NewNopLogger(), db, nil, true, 10000)
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.localDB = localDB
+	// nothing is written into the db of the state
+	require.Nil(t, db.Get(lastUnlockTimeKey))
+	require.True(t, app.loadLastUnlockTime(4).IsZero())
+	require.Equal(t, start.Add(40*time.Second), app.loadLastUnlockTime(5))
+}
@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// admin routes of the plugin holder
+const (
+	RouteList    = "/plugins"
+	RouteStatus  = "/plugins/status"
+	RouteLoad    = "/plugins/load"
+	RouteEnable  = "/plugins/{name}/enable"
+	RouteDisable = "/plugins/{name}/disable"
+)
+
+// NamedRoute fills the plugin name into RouteEnable or RouteDisable, the name is escaped
+// because it may contain '/', '?' or '%'
+func NamedRoute(route, name string) string {
+	return strings.Replace(route, "{name}", url.PathEscape(name), 1)
+}
+
+func getPluginName(r *http.Request) (string, error) {
+	return url.PathUnescape(mux.Vars(r)["name"])
+}
+
+// Info describes a loaded plugin
+type Info struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	Enabled  bool      `json:"enabled"`
+	LoadedAt time.Time `json:"loaded_at"`
+	Panics   int64     `json:"panics"`
+	Timeouts int64     `json:"timeouts"`
+	Trips    int64     `json:"trips"` // times of being disabled automatically
+}
+
+// Status describes the plugin holder
+type Status struct {
+	PluginsDir   string      `json:"plugins_dir"`
+	Plugins      []Info      `json:"plugins"`
+	LastLoadTime time.Time   `json:"last_load_time"`
+	LoadErrors   []LoadError `json:"load_errors"`
+	// the notifications dropped because the observers are too slow
+	DroppedNotifications int64 `json:"dropped_notifications"`
+}
+
+// GetPluginInfos returns all the loaded plugins, in pipeline order
+func (loader *Holder) GetPluginInfos() []Info {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+
+	infos := make([]Info, 0, len(loader.plugins))
+	for _, entry := range loader.plugins {
+		infos = append(infos, Info{
+			Name:     entry.instance.Name(),
+			Path:     entry.path,
+			Enabled:  entry.enabled(),
+			LoadedAt: entry.loadedAt,
+			Panics:   atomic.LoadInt64(&entry.counters.panicCount),
+			Timeouts: atomic.LoadInt64(&entry.counters.timeoutCount),
+			Trips:    atomic.LoadInt64(&entry.counters.tripCount),
+		})
+	}
+	return infos
+}
+
+func (loader *Holder) GetStatus() Status {
+	infos := loader.GetPluginInfos()
+
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	return Status{
+		PluginsDir:   getPluginsDir(),
+		Plugins:      infos,
+		LastLoadTime: loader.lastLoadTime,
+		LoadErrors:   loader.loadErrors,
+
+		DroppedNotifications: atomic.LoadInt64(&loader.droppedNotifications),
+	}
+}
+
+// RegisterAdminRoutes registers the routes used by `cetd plugin` commands
+func (loader *Holder) RegisterAdminRoutes(r *mux.Router) {
+	r.HandleFunc(RouteList, func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, loader.GetPluginInfos())
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc(RouteStatus, func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, loader.GetStatus())
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc(RouteLoad, func(w http.ResponseWriter, _ *http.Request) {
+		loader.LoadPlugins()
+		admin.WriteJSON(w, loader.GetStatus())
+	}).Methods(http.MethodPost)
+
+	r.HandleFunc(RouteEnable, func(w http.ResponseWriter, r *http.Request) {
+		name, err := getPluginName(r)
+		if err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := loader.EnablePlugin(name); err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		admin.WriteJSON(w, loader.GetPluginInfos())
+	}).Methods(http.MethodPost)
+
+	r.HandleFunc(RouteDisable, func(w http.ResponseWriter, r *http.Request) {
+		name, err := getPluginName(r)
+		if err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := loader.DisablePlugin(name); err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		admin.WriteJSON(w, loader.GetPluginInfos())
+	}).Methods(http.MethodPost)
+}
@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/dex/app/admin"
+)
+
+func TestAdminRoutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugin_admin")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	holder := Holder{logger: log.NewNopLogger()}
+	for _, name := range []string{"a", "b", "c/d?e%"} {
+		entry, err := holder.addPlugin(name+".so", namedPlugin(name))
+		require.Nil(t, err)
+		holder.enablePlugin(entry)
+	}
+
+	server := admin.NewServer(log.NewNopLogger())
+	holder.RegisterAdminRoutes(server.Router())
+	sockPath := path.Join(dir, admin.SocketFile)
+	require.Nil(t, server.Start(sockPath))
+	client := admin.NewClient(sockPath)
+
+	var infos []Info
+	require.Nil(t, client.Get(RouteList, &infos))
+	require.Equal(t, 3, len(infos))
+	require.Equal(t, "a", infos[0].Name)
+	require.Equal(t, "a.so", infos[0].Path)
+	require.True(t, infos[0].Enabled)
+
+	require.Nil(t, client.Post(NamedRoute(RouteDisable, "a"), nil, &infos))
+	require.False(t, infos[0].Enabled)
+	require.True(t, infos[1].Enabled)
+	require.Equal(t, 2, len(holder.GetPlugins()))
+
+	require.Nil(t, client.Post(NamedRoute(RouteEnable, "a"), nil, &infos))
+	require.True(t, infos[0].Enabled)
+
+	err = client.Post(NamedRoute(RouteEnable, "c"), nil, &infos)
+	require.NotNil(t, err)
+
+	require.Nil(t, client.Post(NamedRoute(RouteDisable, "c/d?e%"), nil, &infos))
+	require.Equal(t, "c/d?e%", infos[2].Name)
+	require.False(t, infos[2].Enabled)
+
+	var status Status
+	require.Nil(t, client.Get(RouteStatus, &status))
+	require.Equal(t, 3, len(status.Plugins))
+	require.Empty(t, status.LoadErrors)
+}
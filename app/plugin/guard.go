@@ -0,0 +1,162 @@
+package plugin
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// flags (or keys in app.toml) for guarding the calls to PreCheckTx
+const (
+	FlagCallTimeout = "plugin-call-timeout"
+	FlagMaxFailures = "plugin-max-failures"
+)
+
+const (
+	DefaultCallTimeout = 200 * time.Millisecond
+	DefaultMaxFailures = 3
+)
+
+type callResult int
+
+const (
+	callOK callResult = iota
+	callPanicked
+	callTimedOut
+)
+
+// counters of a plugin's failures, which are updated atomically
+type failureCounters struct {
+	consecutiveFailures int64
+	panicCount          int64
+	timeoutCount        int64
+	tripCount           int64
+}
+
+// SetCallPolicy sets the deadline of each PreCheckTx call, and the number of consecutive
+// failures, which are panics or timeouts, after which a plugin is disabled automatically.
+// Any call which returns in time resets the count. Non-positive values are replaced by the defaults.
+func (loader *Holder) SetCallPolicy(timeout time.Duration, maxFailures int) {
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	atomic.StoreInt64(&loader.callTimeout, int64(timeout))
+	atomic.StoreInt64(&loader.maxFailures, int64(maxFailures))
+}
+
+func (loader *Holder) getCallPolicy() (time.Duration, int64) {
+	timeout := time.Duration(atomic.LoadInt64(&loader.callTimeout))
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	maxFailures := atomic.LoadInt64(&loader.maxFailures)
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	return timeout, maxFailures
+}
+
+// RunPreCheckTx runs the enabled plugins as a pipeline. It stops at the first plugin
+// which rejects the tx, and returns the plugin's name together with the error.
+// A plugin which panics or misses the deadline is skipped, as if it accepted the tx.
+// The stores given to the stateful plugins are invalidated when a call misses the deadline
+// and when RunPreCheckTx returns, so the abandoned calls panic instead of racing the check state.
+func (loader *Holder) RunPreCheckTx(req abci.RequestCheckTx, txDecoder sdk.TxDecoder, logger log.Logger) (string, sdk.Error) {
+	timeout, maxFailures := loader.getCallPolicy()
+	var ctx *sdk.Context
+	var guard *storeGuard
+	defer func() {
+		if guard != nil {
+			guard.invalidate()
+		}
+	}()
+	for _, entry := range loader.getEnabledEntries() {
+		instance := entry.instance // captured by the calls which may outlive this iteration
+		check := func() sdk.Error {
+			return instance.PreCheckTx(req, txDecoder, logger)
+		}
+		if p, ok := entry.instance.(StatefulPlugin); ok && loader.newContext != nil {
+			if ctx == nil {
+				guard = &storeGuard{}
+				newCtx := withGuardedStores(loader.newContext(), guard)
+				ctx = &newCtx
+			}
+			pluginCtx := *ctx
+			check = func() sdk.Error {
+				return p.PreCheckTxWithState(pluginCtx, loader.querier, req, txDecoder, logger)
+			}
+		}
+		res, err := callPreCheckTx(entry.instance, check, logger, timeout)
+		if res == callTimedOut && ctx != nil {
+			// the next stateful plugin gets a new context
+			guard.invalidate()
+			ctx, guard = nil, nil
+		}
+		if res == callOK {
+			atomic.StoreInt64(&entry.counters.consecutiveFailures, 0)
+			if err != nil {
+				return entry.instance.Name(), err
+			}
+			continue
+		}
+		loader.onCallFailure(entry, res, maxFailures, logger)
+	}
+	return "", nil
+}
+
+func (loader *Holder) onCallFailure(entry *pluginEntry, res callResult, maxFailures int64, logger log.Logger) {
+	name := entry.instance.Name()
+	if res == callPanicked {
+		atomic.AddInt64(&entry.counters.panicCount, 1)
+	} else {
+		atomic.AddInt64(&entry.counters.timeoutCount, 1)
+		logger.Error(fmt.Sprintf("plugin %s missed the deadline of PreCheckTx", name))
+	}
+
+	if atomic.AddInt64(&entry.counters.consecutiveFailures, 1) < maxFailures {
+		return
+	}
+	if atomic.CompareAndSwapInt32(&entry.isEnabled, 1, 0) {
+		atomic.AddInt64(&entry.counters.tripCount, 1)
+		logger.Error(fmt.Sprintf("plugin %s is disabled after %d consecutive failures", name, maxFailures))
+	}
+}
+
+func callPreCheckTx(p AppPlugin, check func() sdk.Error, logger log.Logger, timeout time.Duration) (callResult, sdk.Error) {
+
+	type output struct {
+		err sdk.Error
+		res callResult
+	}
+	// buffered, so that a plugin which misses the deadline does not block forever
+	c := make(chan output, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r == errContextExpired {
+				logger.Error(fmt.Sprintf("plugin %s uses its context after missing the deadline", p.Name()))
+				c <- output{res: callPanicked}
+			} else if r != nil {
+				logger.Error(fmt.Sprintf("plugin %s panics in PreCheckTx: %v, %s", p.Name(), r, string(debug.Stack())))
+				c <- output{res: callPanicked}
+			}
+		}()
+		c <- output{err: check(), res: callOK}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case out := <-c:
+		return out.res, out.err
+	case <-timer.C:
+		return callTimedOut, nil
+	}
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type badPlugin struct {
+	namedPlugin
+	sleep  time.Duration
+	panics bool
+	reject bool
+	// panics only on this tx if it is set
+	panicsOn string
+}
+
+func (p badPlugin) PreCheckTx(req abci.RequestCheckTx, _ sdk.TxDecoder, _ log.Logger) sdk.Error {
+	time.Sleep(p.sleep)
+	if p.panics || (p.panicsOn != "" && string(req.Tx) == p.panicsOn) {
+		panic("bad plugin")
+	}
+	if p.reject {
+		return sdk.ErrUnauthorized("rejected by " + p.Name())
+	}
+	return nil
+}
+
+func TestRunPreCheckTx(t *testing.T) {
+	logger := log.NewNopLogger()
+	holder := Holder{logger: logger}
+	holder.SetCallPolicy(20*time.Millisecond, 2)
+	plugins := []AppPlugin{
+		badPlugin{namedPlugin: "panicking", panics: true},
+		badPlugin{namedPlugin: "slow", sleep: 100 * time.Millisecond},
+		badPlugin{namedPlugin: "rejecting", reject: true},
+		badPlugin{namedPlugin: "unreachable", panics: true},
+	}
+	for _, p := range plugins {
+		entry, err := holder.addPlugin(p.Name()+".so", p)
+		require.Nil(t, err)
+		holder.enablePlugin(entry)
+	}
+
+	name, err := holder.RunPreCheckTx(abci.RequestCheckTx{}, nil, logger)
+	require.Equal(t, "rejecting", name)
+	require.NotNil(t, err)
+	require.Equal(t, 4, len(holder.GetPlugins()))
+
+	// the panicking and the slow plugins are tripped at the second failure
+	name, _ = holder.RunPreCheckTx(abci.RequestCheckTx{}, nil, logger)
+	require.Equal(t, "rejecting", name)
+	require.Equal(t, 2, len(holder.GetPlugins()))
+	name, _ = holder.RunPreCheckTx(abci.RequestCheckTx{}, nil, logger)
+	require.Equal(t, "rejecting", name)
+
+	infos := holder.GetPluginInfos()
+	require.Equal(t, int64(2), infos[0].Panics)
+	require.Equal(t, int64(1), infos[0].Trips)
+	require.False(t, infos[0].Enabled)
+	require.Equal(t, int64(2), infos[1].Timeouts)
+	require.Equal(t, int64(1), infos[1].Trips)
+	require.False(t, infos[1].Enabled)
+	require.Equal(t, int64(0), infos[2].Trips)
+	require.Equal(t, int64(0), infos[3].Panics)
+
+	// re-enabling resets the consecutive timeouts
+	require.Nil(t, holder.EnablePlugin("slow"))
+	_, _ = holder.RunPreCheckTx(abci.RequestCheckTx{}, nil, logger)
+	require.True(t, holder.GetPluginInfos()[1].Enabled)
+}
+
+func TestPanicsResetByOKCalls(t *testing.T) {
+	logger := log.NewNopLogger()
+	holder := Holder{logger: logger}
+	holder.SetCallPolicy(time.Second, 2)
+	entry, err := holder.addPlugin("flaky.so", badPlugin{namedPlugin: "flaky", panicsOn: "bad"})
+	require.Nil(t, err)
+	holder.enablePlugin(entry)
+
+	bad, good := abci.RequestCheckTx{Tx: []byte("bad")}, abci.RequestCheckTx{Tx: []byte("good")}
+	for i := 0; i < 3; i++ {
+		_, _ = holder.RunPreCheckTx(bad, nil, logger)
+		_, _ = holder.RunPreCheckTx(good, nil, logger)
+	}
+	require.True(t, holder.GetPluginInfos()[0].Enabled)
+	_, _ = holder.RunPreCheckTx(bad, nil, logger)
+	_, _ = holder.RunPreCheckTx(bad, nil, logger)
+	require.False(t, holder.GetPluginInfos()[0].Enabled)
+	require.Equal(t, int64(5), holder.GetPluginInfos()[0].Panics)
+}
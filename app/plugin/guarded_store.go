@@ -0,0 +1,215 @@
+package plugin
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/store/cachekv"
+	"github.com/cosmos/cosmos-sdk/store/tracekv"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// errContextExpired is the panic value of the accesses to a stateful plugin's stores
+// after its call to PreCheckTxWithState has returned or missed the deadline
+var errContextExpired = errors.New("the context of PreCheckTxWithState is used after the call")
+
+// storeGuard invalidates the stores given to a stateful plugin, so that a call which
+// misses the deadline can not keep reading the check state while it is being changed.
+// The accesses hold the read lock, so invalidate waits for the ongoing one to finish.
+type storeGuard struct {
+	mtx     sync.RWMutex
+	invalid bool
+}
+
+func (g *storeGuard) enter() {
+	g.mtx.RLock()
+	if g.invalid {
+		g.mtx.RUnlock()
+		panic(errContextExpired)
+	}
+}
+
+func (g *storeGuard) exit() {
+	g.mtx.RUnlock()
+}
+
+func (g *storeGuard) invalidate() {
+	g.mtx.Lock()
+	g.invalid = true
+	g.mtx.Unlock()
+}
+
+// withGuardedStores returns a copy of ctx whose multistore can only be accessed until guard is invalidated
+func withGuardedStores(ctx sdk.Context, guard *storeGuard) sdk.Context {
+	cms, ok := ctx.MultiStore().(sdk.CacheMultiStore)
+	if !ok {
+		cms = ctx.MultiStore().CacheMultiStore()
+	}
+	return ctx.WithMultiStore(guardedMultiStore{cms: cms, guard: guard})
+}
+
+//=========================
+
+// guardedMultiStore wraps a CacheMultiStore, and guards its KVStores and writes.
+// The guarded operations never call each other, since the read lock is not reentrant.
+type guardedMultiStore struct {
+	cms   sdk.CacheMultiStore
+	guard *storeGuard
+}
+
+var _ sdk.CacheMultiStore = guardedMultiStore{}
+
+func (ms guardedMultiStore) GetStoreType() sdk.StoreType {
+	return ms.cms.GetStoreType()
+}
+
+func (ms guardedMultiStore) CacheWrap() sdk.CacheWrap {
+	return ms.CacheMultiStore()
+}
+
+func (ms guardedMultiStore) CacheWrapWithTrace(_ io.Writer, _ sdk.TraceContext) sdk.CacheWrap {
+	return ms.CacheMultiStore()
+}
+
+func (ms guardedMultiStore) CacheMultiStore() sdk.CacheMultiStore {
+	return guardedMultiStore{cms: ms.cms.CacheMultiStore(), guard: ms.guard}
+}
+
+func (ms guardedMultiStore) CacheMultiStoreWithVersion(version int64) (sdk.CacheMultiStore, error) {
+	cms, err := ms.cms.CacheMultiStoreWithVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return guardedMultiStore{cms: cms, guard: ms.guard}, nil
+}
+
+func (ms guardedMultiStore) GetStore(key sdk.StoreKey) sdk.Store {
+	return ms.GetKVStore(key)
+}
+
+func (ms guardedMultiStore) GetKVStore(key sdk.StoreKey) sdk.KVStore {
+	return guardedKVStore{store: ms.cms.GetKVStore(key), guard: ms.guard}
+}
+
+func (ms guardedMultiStore) TracingEnabled() bool {
+	return ms.cms.TracingEnabled()
+}
+
+func (ms guardedMultiStore) SetTracer(w io.Writer) sdk.MultiStore {
+	ms.cms.SetTracer(w)
+	return ms
+}
+
+func (ms guardedMultiStore) SetTracingContext(tc sdk.TraceContext) sdk.MultiStore {
+	ms.cms.SetTracingContext(tc)
+	return ms
+}
+
+func (ms guardedMultiStore) Write() {
+	ms.guard.enter()
+	defer ms.guard.exit()
+	ms.cms.Write()
+}
+
+//=========================
+
+type guardedKVStore struct {
+	store sdk.KVStore
+	guard *storeGuard
+}
+
+var _ sdk.KVStore = guardedKVStore{}
+
+func (s guardedKVStore) GetStoreType() sdk.StoreType {
+	return s.store.GetStoreType()
+}
+
+func (s guardedKVStore) CacheWrap() sdk.CacheWrap {
+	return cachekv.NewStore(s)
+}
+
+func (s guardedKVStore) CacheWrapWithTrace(w io.Writer, tc sdk.TraceContext) sdk.CacheWrap {
+	return cachekv.NewStore(tracekv.NewStore(s, w, tc))
+}
+
+func (s guardedKVStore) Get(key []byte) []byte {
+	s.guard.enter()
+	defer s.guard.exit()
+	return s.store.Get(key)
+}
+
+func (s guardedKVStore) Has(key []byte) bool {
+	s.guard.enter()
+	defer s.guard.exit()
+	return s.store.Has(key)
+}
+
+func (s guardedKVStore) Set(key, value []byte) {
+	s.guard.enter()
+	defer s.guard.exit()
+	s.store.Set(key, value)
+}
+
+func (s guardedKVStore) Delete(key []byte) {
+	s.guard.enter()
+	defer s.guard.exit()
+	s.store.Delete(key)
+}
+
+func (s guardedKVStore) Iterator(start, end []byte) sdk.Iterator {
+	s.guard.enter()
+	defer s.guard.exit()
+	return guardedIterator{it: s.store.Iterator(start, end), guard: s.guard}
+}
+
+func (s guardedKVStore) ReverseIterator(start, end []byte) sdk.Iterator {
+	s.guard.enter()
+	defer s.guard.exit()
+	return guardedIterator{it: s.store.ReverseIterator(start, end), guard: s.guard}
+}
+
+//=========================
+
+type guardedIterator struct {
+	it    sdk.Iterator
+	guard *storeGuard
+}
+
+func (it guardedIterator) Domain() (start, end []byte) {
+	return it.it.Domain()
+}
+
+func (it guardedIterator) Valid() bool {
+	it.guard.enter()
+	defer it.guard.exit()
+	return it.it.Valid()
+}
+
+func (it guardedIterator) Next() {
+	it.guard.enter()
+	defer it.guard.exit()
+	it.it.Next()
+}
+
+func (it guardedIterator) Key() []byte {
+	it.guard.enter()
+	defer it.guard.exit()
+	return it.it.Key()
+}
+
+func (it guardedIterator) Value() []byte {
+	it.guard.enter()
+	defer it.guard.exit()
+	return it.it.Value()
+}
+
+// Close does not panic after the guard is invalidated, since it is usually deferred,
+// and the iterator is abandoned together with its stores then.
+func (it guardedIterator) Close() {
+	it.guard.mtx.RLock()
+	defer it.guard.mtx.RUnlock()
+	if !it.guard.invalid {
+		it.it.Close()
+	}
+}
@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// NotifyDeliverTx passes the result of DeliverTx to the enabled PostDeliverTxObservers
+func (loader *Holder) NotifyDeliverTx(req abci.RequestDeliverTx, res abci.ResponseDeliverTx, logger log.Logger) {
+	for _, p := range loader.GetPlugins() {
+		if o, ok := p.(PostDeliverTxObserver); ok {
+			loader.observe(p, logger, func() { o.PostDeliverTx(req, res, logger) })
+		}
+	}
+}
+
+// NotifyBeginBlock passes the result of BeginBlock to the enabled BlockObservers
+func (loader *Holder) NotifyBeginBlock(req abci.RequestBeginBlock, res abci.ResponseBeginBlock, logger log.Logger) {
+	for _, p := range loader.GetPlugins() {
+		if o, ok := p.(BlockObserver); ok {
+			loader.observe(p, logger, func() { o.PostBeginBlock(req, res, logger) })
+		}
+	}
+}
+
+// NotifyEndBlock passes the result of EndBlock to the enabled BlockObservers
+func (loader *Holder) NotifyEndBlock(req abci.RequestEndBlock, res abci.ResponseEndBlock, logger log.Logger) {
+	for _, p := range loader.GetPlugins() {
+		if o, ok := p.(BlockObserver); ok {
+			loader.observe(p, logger, func() { o.PostEndBlock(req, res, logger) })
+		}
+	}
+}
+
+// NotifyCommit passes the result of Commit to the enabled CommitObservers
+func (loader *Holder) NotifyCommit(res abci.ResponseCommit, logger log.Logger) {
+	for _, p := range loader.GetPlugins() {
+		if o, ok := p.(CommitObserver); ok {
+			loader.observe(p, logger, func() { o.PostCommit(res, logger) })
+		}
+	}
+}
+
+// ObserverQueueSize is the number of notifications which can wait for the observers.
+// The notifications beyond it are dropped.
+const ObserverQueueSize = 4096
+
+// The observers are exempt from the deadline and the failure breaker of PreCheckTx, since
+// they are run by a background goroutine, one notification after another, off the consensus
+// path. A slow observer only delays the other observers, and makes the notifications
+// dropped when the queue is full, while a panic in it is recovered and logged.
+func (loader *Holder) observe(p AppPlugin, logger log.Logger, f func()) {
+	loader.startObservers()
+	select {
+	case loader.observerQueue <- func() { runObserver(p, logger, f) }:
+	default:
+		atomic.AddInt64(&loader.droppedNotifications, 1)
+		logger.Error(fmt.Sprintf("the queue of observers is full, a notification to plugin %s is dropped", p.Name()))
+	}
+}
+
+func (loader *Holder) startObservers() {
+	loader.observerOnce.Do(func() {
+		loader.observerQueue = make(chan func(), ObserverQueueSize)
+		go func() {
+			for f := range loader.observerQueue {
+				f()
+			}
+		}()
+	})
+}
+
+// syncObservers waits until the observers have handled the notifications queued before
+func (loader *Holder) syncObservers() {
+	loader.startObservers()
+	done := make(chan struct{})
+	loader.observerQueue <- func() { close(done) }
+	<-done
+}
+
+func runObserver(p AppPlugin, logger log.Logger, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(fmt.Sprintf("plugin %s panics when observing: %v, %s", p.Name(), r, string(debug.Stack())))
+		}
+	}()
+	f()
+}
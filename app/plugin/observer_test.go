@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type observerPlugin struct {
+	namedPlugin
+	deliverTxCount  int
+	beginBlockCount int
+	endBlockCount   int
+	commitCount     int
+}
+
+func (p *observerPlugin) PostDeliverTx(abci.RequestDeliverTx, abci.ResponseDeliverTx, log.Logger) {
+	p.deliverTxCount++
+}
+
+func (p *observerPlugin) PostBeginBlock(abci.RequestBeginBlock, abci.ResponseBeginBlock, log.Logger) {
+	p.beginBlockCount++
+}
+
+func (p *observerPlugin) PostEndBlock(abci.RequestEndBlock, abci.ResponseEndBlock, log.Logger) {
+	p.endBlockCount++
+}
+
+func (p *observerPlugin) PostCommit(abci.ResponseCommit, log.Logger) {
+	p.commitCount++
+	panic("panic in PostCommit")
+}
+
+func TestObservers(t *testing.T) {
+	logger := log.NewNopLogger()
+	holder := Holder{logger: logger}
+	observer := &observerPlugin{namedPlugin: "observer"}
+	for _, p := range []AppPlugin{namedPlugin("filter"), observer} {
+		entry, err := holder.addPlugin(p.Name()+".so", p)
+		require.Nil(t, err)
+		holder.enablePlugin(entry)
+	}
+
+	holder.NotifyBeginBlock(abci.RequestBeginBlock{}, abci.ResponseBeginBlock{}, logger)
+	holder.NotifyDeliverTx(abci.RequestDeliverTx{}, abci.ResponseDeliverTx{}, logger)
+	holder.NotifyDeliverTx(abci.RequestDeliverTx{}, abci.ResponseDeliverTx{}, logger)
+	holder.NotifyEndBlock(abci.RequestEndBlock{}, abci.ResponseEndBlock{}, logger)
+	require.NotPanics(t, func() {
+		holder.NotifyCommit(abci.ResponseCommit{}, logger)
+	})
+	holder.syncObservers()
+	require.Equal(t, 1, observer.beginBlockCount)
+	require.Equal(t, 2, observer.deliverTxCount)
+	require.Equal(t, 1, observer.endBlockCount)
+	require.Equal(t, 1, observer.commitCount)
+
+	// disabled observers are not notified
+	require.Nil(t, holder.DisablePlugin("observer"))
+	holder.NotifyDeliverTx(abci.RequestDeliverTx{}, abci.ResponseDeliverTx{}, logger)
+	holder.syncObservers()
+	require.Equal(t, 2, observer.deliverTxCount)
+}
+
+type slowObserver struct {
+	namedPlugin
+	blocked chan struct{}
+}
+
+func (p *slowObserver) PostCommit(abci.ResponseCommit, log.Logger) {
+	<-p.blocked
+}
+
+func TestSlowObserver(t *testing.T) {
+	logger := log.NewNopLogger()
+	holder := Holder{logger: logger}
+	observer := &slowObserver{namedPlugin: "slow", blocked: make(chan struct{})}
+	require.Nil(t, holder.RegisterPlugin(observer))
+
+	// the blocked observer neither blocks Commit nor makes the queue grow without limit
+	for i := 0; i < ObserverQueueSize+10; i++ {
+		holder.NotifyCommit(abci.ResponseCommit{}, logger)
+	}
+	require.True(t, holder.GetStatus().DroppedNotifications >= 10)
+	close(observer.blocked)
+	holder.syncObservers()
+	require.True(t, holder.GetPluginInfos()[0].Enabled)
+}
@@ -10,3 +10,36 @@ type AppPlugin interface {
 	PreCheckTx(abci.RequestCheckTx, sdk.TxDecoder, log.Logger) sdk.Error
 	Name() string
 }
+
+// The following interfaces are optional for an AppPlugin. They are only for observing,
+// so the requests and responses passed to them must be treated as read-only. They are
+// called in order by a background goroutine, after the corresponding ABCI call returns,
+// and a panic in them is recovered and logged without affecting consensus.
+
+// PostDeliverTxObserver observes the result of each DeliverTx
+type PostDeliverTxObserver interface {
+	PostDeliverTx(abci.RequestDeliverTx, abci.ResponseDeliverTx, log.Logger)
+}
+
+// BlockObserver observes the results of BeginBlock and EndBlock
+type BlockObserver interface {
+	PostBeginBlock(abci.RequestBeginBlock, abci.ResponseBeginBlock, log.Logger)
+	PostEndBlock(abci.RequestEndBlock, abci.ResponseEndBlock, log.Logger)
+}
+
+// CommitObserver observes the result of each Commit
+type CommitObserver interface {
+	PostCommit(abci.ResponseCommit, log.Logger)
+}
+
+// Reloader is implemented by plugins which reload their settings when the plugins are loaded again
+type Reloader interface {
+	Reload() error
+}
+
+// StatefulPlugin is implemented by plugins which check txs against the chain state.
+// PreCheckTxWithState is called instead of PreCheckTx, with a context on the check state,
+// whose writes are discarded. The context must not be used after the call returns.
+type StatefulPlugin interface {
+	PreCheckTxWithState(sdk.Context, StateQuerier, abci.RequestCheckTx, sdk.TxDecoder, log.Logger) sdk.Error
+}
@@ -2,18 +2,34 @@ package plugin
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path"
 	"plugin"
 	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/viper"
 	"github.com/tendermint/tendermint/libs/log"
 )
 
+// PluginsDir is the directory under the node's home from which plugins are loaded
+const PluginsDir = "data/plugins"
+
+// LegacyPluginFile is the only plugin loaded by the earlier versions, which is still loaded before
+// the ones in PluginsDir. It is deprecated, please move it into PluginsDir.
+const LegacyPluginFile = "data/plugin.so"
+
+// BuiltinPath is reported as the path of plugins registered by RegisterPlugin
+const BuiltinPath = "builtin"
+
 var reloadPluginSignal os.Signal
 
 func SetReloadPluginSignal(signal os.Signal) {
@@ -34,24 +50,116 @@ func (loader *Holder) WaitPluginToggleSignal(logger log.Logger) {
 	go togglePlugin(c)
 }
 
+type pluginEntry struct {
+	isEnabled int32
+	path      string
+	instance  AppPlugin
+	loadedAt  time.Time
+	counters  failureCounters
+}
+
+func (entry *pluginEntry) enabled() bool {
+	return atomic.LoadInt32(&entry.isEnabled) == 1
+}
+
+// Holder keeps the plugins loaded from PluginsDir, ordered by their file names.
+// Enabled plugins form the pipeline which is run by CheckTx.
 type Holder struct {
-	isEnabled      int32
-	pluginInstance AppPlugin
-	logger         log.Logger
+	callTimeout  int64 // time.Duration
+	maxFailures  int64
+	mtx          sync.RWMutex
+	plugins      []*pluginEntry
+	lastLoadTime time.Time
+	loadErrors   []LoadError
+	logger       log.Logger
+
+	newContext func() sdk.Context
+	querier    StateQuerier
+
+	observerOnce         sync.Once
+	observerQueue        chan func()
+	droppedNotifications int64
+}
+
+// LoadError records why a plugin file could not be loaded
+type LoadError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
 }
 
 func (loader *Holder) isPluginLoaded() bool {
-	return loader.pluginInstance != nil
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	return len(loader.plugins) != 0
 }
 
-func (loader *Holder) GetPlugin() AppPlugin {
-	if loader.isPluginEnabled() {
-		return loader.pluginInstance
+// GetPlugins returns the enabled plugins, in pipeline order
+func (loader *Holder) GetPlugins() []AppPlugin {
+	entries := loader.getEnabledEntries()
+	res := make([]AppPlugin, len(entries))
+	for i, entry := range entries {
+		res[i] = entry.instance
 	}
+	return res
+}
 
+func (loader *Holder) getEnabledEntries() []*pluginEntry {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+
+	var res []*pluginEntry
+	for _, entry := range loader.plugins {
+		if entry.enabled() {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+// RegisterPlugin adds a plugin which is built into the node to the end of the pipeline, and enables it
+func (loader *Holder) RegisterPlugin(instance AppPlugin) error {
+	entry, err := loader.addPlugin(BuiltinPath, instance)
+	if err != nil {
+		return err
+	}
+	loader.enablePlugin(entry)
+	return nil
+}
+
+// EnablePlugin enables the loaded plugin whose Name() is name
+func (loader *Holder) EnablePlugin(name string) error {
+	entry := loader.findPlugin(name)
+	if entry == nil {
+		return fmt.Errorf("plugin %s not loaded", name)
+	}
+	loader.enablePlugin(entry)
 	return nil
 }
 
+// DisablePlugin disables the loaded plugin whose Name() is name
+func (loader *Holder) DisablePlugin(name string) error {
+	entry := loader.findPlugin(name)
+	if entry == nil {
+		return fmt.Errorf("plugin %s not loaded", name)
+	}
+	loader.disablePlugin(entry)
+	return nil
+}
+
+func (loader *Holder) findPlugin(name string) *pluginEntry {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+
+	for _, entry := range loader.plugins {
+		if entry.instance.Name() == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// togglePlugin disables all the plugins if any is enabled. Otherwise it loads the plugins like
+// LoadPlugins, which reloads the rules files and the plugins supporting Reloader, and enables them all.
 func (loader *Holder) togglePlugin() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -60,70 +168,200 @@ func (loader *Holder) togglePlugin() {
 	}()
 
 	if !loader.isPluginLoaded() {
-		loader.loadAndEnablePlugin()
+		loader.LoadPlugins()
 		return
 	}
 
-	if loader.isPluginEnabled() {
-		loader.disablePlugin()
+	if len(loader.GetPlugins()) != 0 {
+		loader.disableAllPlugins()
 	} else {
-		loader.enablePlugin()
+		loader.LoadPlugins()
+		loader.enableAllPlugins()
+	}
+}
+
+func (loader *Holder) enableAllPlugins() {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	for _, entry := range loader.plugins {
+		loader.enablePlugin(entry)
+	}
+}
+
+func (loader *Holder) disableAllPlugins() {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	for _, entry := range loader.plugins {
+		loader.disablePlugin(entry)
 	}
 }
 
-func (loader *Holder) isPluginEnabled() bool {
-	return atomic.LoadInt32(&loader.isEnabled) == 1
+func (loader *Holder) enablePlugin(entry *pluginEntry) {
+	atomic.StoreInt64(&entry.counters.consecutiveFailures, 0)
+	atomic.StoreInt32(&entry.isEnabled, 1)
+	loader.logger.Info(fmt.Sprintf("plugin %s is enabled", entry.instance.Name()))
 }
 
-func (loader *Holder) enablePlugin() {
-	atomic.StoreInt32(&loader.isEnabled, 1)
+func (loader *Holder) disablePlugin(entry *pluginEntry) {
+	atomic.StoreInt32(&entry.isEnabled, 0)
+	loader.logger.Info(fmt.Sprintf("plugin %s is disabled", entry.instance.Name()))
+}
 
-	if loader.pluginInstance != nil {
-		loader.logger.Info(fmt.Sprintf("plugin %s is enabled", loader.pluginInstance.Name()))
+// addPlugin appends instance to the pipeline, the names of plugins must be unique
+func (loader *Holder) addPlugin(pluginPath string, instance AppPlugin) (*pluginEntry, error) {
+	loader.mtx.Lock()
+	defer loader.mtx.Unlock()
+
+	for _, entry := range loader.plugins {
+		if entry.instance.Name() == instance.Name() {
+			return nil, fmt.Errorf("plugin %s in %s conflicts with the one in %s",
+				instance.Name(), pluginPath, entry.path)
+		}
 	}
+	entry := &pluginEntry{path: pluginPath, instance: instance, loadedAt: time.Now()}
+	loader.plugins = append(loader.plugins, entry)
+	return entry, nil
 }
 
-func (loader *Holder) disablePlugin() {
-	atomic.StoreInt32(&loader.isEnabled, 0)
+func (loader *Holder) getAllEntries() []*pluginEntry {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	return append([]*pluginEntry(nil), loader.plugins...)
+}
 
-	if loader.pluginInstance != nil {
-		loader.logger.Info(fmt.Sprintf("plugin %s is disabled", loader.pluginInstance.Name()))
+func (loader *Holder) isPathLoaded(pluginPath string) bool {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+
+	for _, entry := range loader.plugins {
+		if entry.path == pluginPath {
+			return true
+		}
 	}
+	return false
 }
 
-func (loader *Holder) loadAndEnablePlugin() {
-	defer func() {
-		if r := recover(); r != nil {
-			loader.logger.Error(fmt.Sprintf("load plugin failed: %s", string(debug.Stack())))
+func fileExists(filePath string) bool {
+	info, err := os.Stat(filePath)
+	return err == nil && !info.IsDir()
+}
+
+func getPluginsDir() string {
+	return path.Join(viper.GetString(flags.FlagHome), PluginsDir)
+}
+
+// LoadPlugins loads and enables the plugins in PluginsDir which have not been loaded yet.
+// The errors met are kept for the status report until the next loading.
+func (loader *Holder) LoadPlugins() {
+	loadErrors := loader.loadAndEnablePlugins()
+
+	loader.mtx.Lock()
+	defer loader.mtx.Unlock()
+	loader.lastLoadTime = time.Now()
+	loader.loadErrors = loadErrors
+}
+
+func (loader *Holder) loadAndEnablePlugins() (loadErrors []LoadError) {
+	if err := loader.loadLegacyPlugin(); err != nil {
+		loadErrors = append(loadErrors, *err)
+	}
+
+	pluginsDir := getPluginsDir()
+	files, err := ioutil.ReadDir(pluginsDir)
+	if err != nil {
+		loader.logger.Error(fmt.Sprintf("read plugins dir %s failed, %s", pluginsDir, err.Error()))
+		return append(loadErrors, LoadError{Path: pluginsDir, Error: err.Error()})
+	}
+
+	// the plugins loaded before are reloaded if they support it
+	for _, entry := range loader.getAllEntries() {
+		if r, ok := entry.instance.(Reloader); ok {
+			if err := r.Reload(); err != nil {
+				loader.logger.Error(fmt.Sprintf("reload plugin %s failed, %s", entry.instance.Name(), err.Error()))
+				loadErrors = append(loadErrors, LoadError{Path: entry.path, Error: err.Error()})
+			} else {
+				loader.logger.Info(fmt.Sprintf("plugin %s is reloaded", entry.instance.Name()))
+			}
 		}
-	}()
+	}
 
-	rootDir := viper.GetString(flags.FlagHome)
-	pluginPath := path.Join(rootDir, "data/plugin.so")
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".so") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
 
-	if _, err := os.Stat(pluginPath); os.IsNotExist(err) {
-		loader.logger.Error(fmt.Sprintf("plugin %s not exists", pluginPath))
-		return
+	for _, name := range names {
+		pluginPath := path.Join(pluginsDir, name)
+		if loader.isPathLoaded(pluginPath) {
+			continue
+		}
+		entry, err := loader.loadPlugin(pluginPath)
+		if err != nil {
+			loader.logger.Error(err.Error())
+			loadErrors = append(loadErrors, LoadError{Path: pluginPath, Error: err.Error()})
+			continue
+		}
+		loader.enablePlugin(entry)
+	}
+
+	if rulesPath := findRulesFile(pluginsDir); rulesPath != "" && !loader.isPathLoaded(rulesPath) {
+		entry, err := loader.loadRuleFilter(rulesPath)
+		if err != nil {
+			loader.logger.Error(err.Error())
+			loadErrors = append(loadErrors, LoadError{Path: rulesPath, Error: err.Error()})
+		} else {
+			loader.enablePlugin(entry)
+		}
+	}
+	return
+}
+
+func (loader *Holder) loadLegacyPlugin() *LoadError {
+	pluginPath := path.Join(viper.GetString(flags.FlagHome), LegacyPluginFile)
+	if !fileExists(pluginPath) || loader.isPathLoaded(pluginPath) {
+		return nil
+	}
+	loader.logger.Error(fmt.Sprintf("%s is deprecated, please move it into %s", pluginPath, getPluginsDir()))
+	entry, err := loader.loadPlugin(pluginPath)
+	if err != nil {
+		loader.logger.Error(err.Error())
+		return &LoadError{Path: pluginPath, Error: err.Error()}
+	}
+	loader.enablePlugin(entry)
+	return nil
+}
+
+func (loader *Holder) loadRuleFilter(rulesPath string) (*pluginEntry, error) {
+	filter, err := NewRuleFilter(rulesPath)
+	if err != nil {
+		return nil, err
 	}
+	return loader.addPlugin(rulesPath, filter)
+}
+
+func (loader *Holder) loadPlugin(pluginPath string) (entry *pluginEntry, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			entry, err = nil, fmt.Errorf("load plugin %s failed: %s", pluginPath, string(debug.Stack()))
+		}
+	}()
 
 	p, err := plugin.Open(pluginPath)
 	if err != nil {
-		loader.logger.Error(fmt.Sprintf("plugin %s open failed, %s", pluginPath, err.Error()))
-		return
+		return nil, fmt.Errorf("plugin %s open failed, %s", pluginPath, err.Error())
 	}
 
 	symbol, err := p.Lookup("Instance")
 	if err != nil {
-		loader.logger.Error(fmt.Sprintf("Lookup Instance in plugin %s failed", pluginPath))
-		return
+		return nil, fmt.Errorf("Lookup Instance in plugin %s failed", pluginPath)
 	}
 
 	instance, ok := symbol.(AppPlugin)
 	if !ok {
-		loader.logger.Error(fmt.Sprintf("Instance in plugin %s is invalid", pluginPath))
-		return
+		return nil, fmt.Errorf("Instance in plugin %s is invalid", pluginPath)
 	}
-
-	loader.pluginInstance = instance
-	loader.enablePlugin()
+	return loader.addPlugin(pluginPath, instance)
 }
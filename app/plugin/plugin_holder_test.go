@@ -1,12 +1,17 @@
 package plugin
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 )
 
@@ -16,7 +21,7 @@ func TestTogglePlugin(t *testing.T) {
 	require.Nil(t, err)
 
 	defer func() {
-		cmd = exec.Command("rm", "./test_plugin/data/plugin.so")
+		cmd = exec.Command("rm", "-r", "./test_plugin/data")
 		_ = cmd.Run()
 	}()
 
@@ -27,20 +32,84 @@ func TestTogglePlugin(t *testing.T) {
 	// invalid path
 	viper.Set(flags.FlagHome, "./invalid/")
 	holder.togglePlugin()
-	require.Equal(t, int32(0), holder.isEnabled)
-	require.Nil(t, holder.GetPlugin())
+	require.False(t, holder.isPluginLoaded())
+	require.Empty(t, holder.GetPlugins())
 
 	// valid path
 	viper.Set(flags.FlagHome, "./test_plugin/")
 	holder.togglePlugin()
-	require.Equal(t, int32(1), holder.isEnabled)
-	require.NotNil(t, holder.GetPlugin())
+	require.True(t, holder.isPluginLoaded())
+	require.Equal(t, 1, len(holder.GetPlugins()))
+	require.Equal(t, "TestPlugin", holder.GetPlugins()[0].Name())
 
 	holder.togglePlugin()
-	require.Equal(t, int32(0), holder.isEnabled)
-	require.Nil(t, holder.GetPlugin())
+	require.Empty(t, holder.GetPlugins())
 
 	holder.togglePlugin()
-	require.Equal(t, int32(1), holder.isEnabled)
-	require.NotNil(t, holder.GetPlugin())
+	require.Equal(t, 1, len(holder.GetPlugins()))
+
+	require.Nil(t, holder.DisablePlugin("TestPlugin"))
+	require.Empty(t, holder.GetPlugins())
+	require.Nil(t, holder.EnablePlugin("TestPlugin"))
+	require.Equal(t, 1, len(holder.GetPlugins()))
+	require.NotNil(t, holder.EnablePlugin("NoSuchPlugin"))
+}
+
+func TestLoadLegacyPlugin(t *testing.T) {
+	home, err := ioutil.TempDir("", "legacy_plugin")
+	require.Nil(t, err)
+	defer os.RemoveAll(home)
+	legacyPath := path.Join(home, LegacyPluginFile)
+	require.Nil(t, os.MkdirAll(path.Dir(legacyPath), 0755))
+	require.Nil(t, ioutil.WriteFile(legacyPath, []byte("not a plugin"), 0644))
+	viperSetHome(home)
+	defer viperSetHome("")
+
+	// the legacy plugin is tried even if PluginsDir does not exist
+	holder := Holder{logger: log.NewNopLogger()}
+	holder.LoadPlugins()
+	loadErrors := holder.GetStatus().LoadErrors
+	require.Equal(t, 2, len(loadErrors))
+	require.Equal(t, legacyPath, loadErrors[0].Path)
+	require.Equal(t, getPluginsDir(), loadErrors[1].Path)
+}
+
+func viperSetHome(home string) {
+	viper.Set(flags.FlagHome, home)
+}
+
+type namedPlugin string
+
+func (p namedPlugin) PreCheckTx(abci.RequestCheckTx, sdk.TxDecoder, log.Logger) sdk.Error {
+	return nil
+}
+
+func (p namedPlugin) Name() string {
+	return string(p)
+}
+
+func TestPluginPipeline(t *testing.T) {
+	holder := Holder{logger: log.NewNopLogger()}
+	for _, name := range []string{"a", "b", "c"} {
+		entry, err := holder.addPlugin(name+".so", namedPlugin(name))
+		require.Nil(t, err)
+		holder.enablePlugin(entry)
+	}
+	_, err := holder.addPlugin("dup.so", namedPlugin("b"))
+	require.NotNil(t, err)
+
+	names := func() []string {
+		var res []string
+		for _, p := range holder.GetPlugins() {
+			res = append(res, p.Name())
+		}
+		return res
+	}
+	require.Equal(t, []string{"a", "b", "c"}, names())
+
+	require.Nil(t, holder.DisablePlugin("b"))
+	require.Equal(t, []string{"a", "c"}, names())
+
+	require.Nil(t, holder.EnablePlugin("b"))
+	require.Equal(t, []string{"a", "b", "c"}, names())
 }
@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/coinexchain/cet-sdk/modules/asset"
+	"github.com/coinexchain/cet-sdk/modules/authx"
+	"github.com/coinexchain/cet-sdk/modules/market"
+)
+
+// StateQuerier is a small read-only facade over the keepers of the app.
+// It is kept stable so that plugins need not depend on the keepers' own APIs.
+type StateQuerier interface {
+	// accountX
+	GetAccountX(ctx sdk.Context, addr sdk.AccAddress) (authx.AccountX, bool)
+	// bankx
+	GetCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
+	GetFrozenCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
+	GetLockedCoins(ctx sdk.Context, addr sdk.AccAddress) authx.LockedCoins
+	// asset
+	GetToken(ctx sdk.Context, symbol string) asset.Token
+	IsTokenForbidden(ctx sdk.Context, symbol string) bool
+	IsForbiddenByTokenIssuer(ctx sdk.Context, symbol string, addr sdk.AccAddress) bool
+	// market
+	GetMarketInfo(ctx sdk.Context, symbol string) (market.MarketInfo, bool)
+	// alias
+	GetAddressFromAlias(ctx sdk.Context, alias string) (sdk.AccAddress, bool)
+	GetAliasListOfAccount(ctx sdk.Context, addr sdk.AccAddress) []string
+}
+
+// SetStateProvider sets the function creating the contexts passed to StatefulPlugins,
+// and the querier over the app's keepers
+func (loader *Holder) SetStateProvider(newContext func() sdk.Context, querier StateQuerier) {
+	loader.newContext = newContext
+	loader.querier = querier
+}
@@ -0,0 +1,285 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	toml "github.com/pelletier/go-toml"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+// RuleFilterName is the name of the built-in plugin which filters txs with declarative rules
+const RuleFilterName = "RuleFilter"
+
+// RulesFiles are the names of the rules files searched in PluginsDir, only the first one found is used
+var RulesFiles = []string{"rules.toml", "rules.json"}
+
+const (
+	CodeSpacePlugin    sdk.CodespaceType = "plugin"
+	CodeRejectedByRule sdk.CodeType      = 2001
+)
+
+// Rule describes the txs to be rejected. All the conditions set in a rule must be met for it to match,
+// and the conditions left empty are ignored.
+type Rule struct {
+	Name string `json:"name" toml:"name"`
+	// matches if any msg in the tx has one of these types, such as "bankx.MsgSend"
+	MsgTypes []string `json:"msg_types" toml:"msg_types"`
+	// matches if the fee amount of FeeDenom is in [MinFee, MaxFee], MaxFee being 0 means no upper bound
+	FeeDenom string `json:"fee_denom" toml:"fee_denom"`
+	MinFee   int64  `json:"min_fee" toml:"min_fee"`
+	MaxFee   int64  `json:"max_fee" toml:"max_fee"`
+	// matches if any signer or recipient is in the blocklists
+	Signers    []string `json:"signers" toml:"signers"`
+	Recipients []string `json:"recipients" toml:"recipients"`
+	// matches if the memo matches this regular expression
+	MemoRegex string `json:"memo_regex" toml:"memo_regex"`
+	// matches if the tx has more msgs than this
+	MaxMsgs int `json:"max_msgs" toml:"max_msgs"`
+}
+
+// Rules is the content of a rules file
+type Rules struct {
+	Rules []Rule `json:"rules" toml:"rules"`
+}
+
+type compiledRule struct {
+	Rule
+	msgTypes   map[string]bool
+	signers    map[string]bool
+	recipients map[string]bool
+	memoRegex  *regexp.Regexp
+	// the fees of a tx may be beyond int64
+	minFee sdk.Int
+	maxFee sdk.Int
+}
+
+// RuleFilter is a built-in AppPlugin, which rejects the txs matching any rule in its rules file
+type RuleFilter struct {
+	mtx      sync.RWMutex
+	filePath string
+	rules    []compiledRule
+}
+
+var _ AppPlugin = (*RuleFilter)(nil)
+var _ Reloader = (*RuleFilter)(nil)
+
+func NewRuleFilter(filePath string) (*RuleFilter, error) {
+	f := &RuleFilter{filePath: filePath}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RuleFilter) Name() string {
+	return RuleFilterName
+}
+
+// Reload reads the rules file again, the current rules are kept if the file is invalid
+func (f *RuleFilter) Reload() error {
+	rules, err := readRules(f.filePath)
+	if err != nil {
+		return err
+	}
+	compiled := make([]compiledRule, len(rules.Rules))
+	for i, rule := range rules.Rules {
+		if compiled[i], err = compileRule(rule); err != nil {
+			return fmt.Errorf("invalid rule %d (%s) in %s: %s", i, rule.Name, f.filePath, err.Error())
+		}
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.rules = compiled
+	return nil
+}
+
+func readRules(filePath string) (rules Rules, err error) {
+	bz, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	if strings.HasSuffix(filePath, ".json") {
+		err = json.Unmarshal(bz, &rules)
+	} else {
+		err = toml.Unmarshal(bz, &rules)
+	}
+	if err != nil {
+		err = fmt.Errorf("parse rules file %s failed, %s", filePath, err.Error())
+	}
+	return
+}
+
+func compileRule(rule Rule) (compiledRule, error) {
+	res := compiledRule{
+		Rule:       rule,
+		msgTypes:   toSet(rule.MsgTypes),
+		signers:    make(map[string]bool, len(rule.Signers)),
+		recipients: make(map[string]bool, len(rule.Recipients)),
+		minFee:     sdk.NewInt(rule.MinFee),
+		maxFee:     sdk.NewInt(rule.MaxFee),
+	}
+	if res.FeeDenom == "" {
+		res.FeeDenom = dex.CET
+	}
+	// AmountOf panics on an invalid denom
+	if !(sdk.Coin{Denom: res.FeeDenom, Amount: sdk.ZeroInt()}).IsValid() {
+		return res, fmt.Errorf("invalid fee_denom: %s", res.FeeDenom)
+	}
+	if res.MaxFee != 0 && res.MaxFee < res.MinFee {
+		return res, fmt.Errorf("max_fee is less than min_fee")
+	}
+	for _, s := range rule.Signers {
+		addr, err := sdk.AccAddressFromBech32(s)
+		if err != nil {
+			return res, err
+		}
+		res.signers[string(addr)] = true
+	}
+	for _, s := range rule.Recipients {
+		addr, err := sdk.AccAddressFromBech32(s)
+		if err != nil {
+			return res, err
+		}
+		res.recipients[string(addr)] = true
+	}
+	if rule.MemoRegex != "" {
+		var err error
+		if res.memoRegex, err = regexp.Compile(rule.MemoRegex); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		set[s] = true
+	}
+	return set
+}
+
+func (f *RuleFilter) PreCheckTx(req abci.RequestCheckTx, txDecoder sdk.TxDecoder, logger log.Logger) sdk.Error {
+	f.mtx.RLock()
+	rules := f.rules
+	f.mtx.RUnlock()
+	if len(rules) == 0 {
+		return nil
+	}
+
+	tx, err := txDecoder(req.Tx)
+	if err != nil {
+		return err
+	}
+	stdTx, ok := tx.(auth.StdTx)
+	if !ok {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.match(stdTx) {
+			return sdk.NewError(CodeSpacePlugin, CodeRejectedByRule, fmt.Sprintf("rejected by rule %s", rule.Name))
+		}
+	}
+	return nil
+}
+
+func (rule compiledRule) match(tx auth.StdTx) bool {
+	if len(rule.msgTypes) != 0 && !rule.matchMsgTypes(tx) {
+		return false
+	}
+	if rule.MinFee != 0 || rule.MaxFee != 0 {
+		fee := tx.Fee.Amount.AmountOf(rule.FeeDenom)
+		if fee.LT(rule.minFee) || (rule.MaxFee != 0 && fee.GT(rule.maxFee)) {
+			return false
+		}
+	}
+	if len(rule.signers) != 0 && !containsAny(rule.signers, tx.GetSigners()) {
+		return false
+	}
+	if len(rule.recipients) != 0 && !containsAny(rule.recipients, getRecipients(tx)) {
+		return false
+	}
+	if rule.memoRegex != nil && !rule.memoRegex.MatchString(tx.Memo) {
+		return false
+	}
+	if rule.MaxMsgs != 0 && len(tx.Msgs) <= rule.MaxMsgs {
+		return false
+	}
+	return true
+}
+
+func (rule compiledRule) matchMsgTypes(tx auth.StdTx) bool {
+	for _, msg := range tx.Msgs {
+		if rule.msgTypes[GetMsgType(msg)] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMsgType returns the type of msg used in rules, which is its route and struct name, such as "bankx.MsgSend"
+func GetMsgType(msg sdk.Msg) string {
+	t := reflect.TypeOf(msg)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return msg.Route() + "." + t.Name()
+}
+
+func containsAny(set map[string]bool, addrs []sdk.AccAddress) bool {
+	for _, addr := range addrs {
+		if set[string(addr)] {
+			return true
+		}
+	}
+	return false
+}
+
+func getRecipients(tx auth.StdTx) []sdk.AccAddress {
+	var res []sdk.AccAddress
+	for _, msg := range tx.Msgs {
+		switch msg := msg.(type) {
+		case bankx.MsgSend:
+			res = append(res, msg.ToAddress)
+		case bankx.MsgSupervisedSend:
+			res = append(res, msg.ToAddress)
+		case bankx.MsgMultiSend:
+			for _, out := range msg.Outputs {
+				res = append(res, out.Address)
+			}
+		case bank.MsgSend:
+			res = append(res, msg.ToAddress)
+		case bank.MsgMultiSend:
+			for _, out := range msg.Outputs {
+				res = append(res, out.Address)
+			}
+		}
+	}
+	return res
+}
+
+func findRulesFile(pluginsDir string) string {
+	for _, name := range RulesFiles {
+		filePath := path.Join(pluginsDir, name)
+		if fileExists(filePath) {
+			return filePath
+		}
+	}
+	return ""
+}
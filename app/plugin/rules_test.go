@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestRuleFilter(t *testing.T) {
+	_, _, from := testutil.KeyPubAddr()
+	_, _, to := testutil.KeyPubAddr()
+	_, _, blocked := testutil.KeyPubAddr()
+
+	dir, err := ioutil.TempDir("", "rule_filter")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	rulesPath := path.Join(dir, "rules.toml")
+	rules := `
+[[rules]]
+name = "big-send"
+msg_types = ["bankx.MsgSend"]
+min_fee = 1000000000000
+
+[[rules]]
+name = "blocked-recipient"
+recipients = ["` + blocked.String() + `"]
+
+[[rules]]
+name = "spam-memo"
+memo_regex = "^airdrop"
+
+[[rules]]
+name = "too-many-msgs"
+max_msgs = 2
+`
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(rules), 0644))
+	filter, err := NewRuleFilter(rulesPath)
+	require.Nil(t, err)
+
+	check := func(fee int64, memo string, msgs ...sdk.Msg) sdk.Error {
+		tx := auth.NewStdTx(msgs, auth.NewStdFee(200000, dex.NewCetCoins(fee)), nil, memo)
+		decoder := func([]byte) (sdk.Tx, sdk.Error) { return tx, nil }
+		return filter.PreCheckTx(abci.RequestCheckTx{}, decoder, log.NewNopLogger())
+	}
+	send := bankx.NewMsgSend(from, to, dex.NewCetCoins(1), 0)
+	require.Nil(t, check(100, "", send))
+	require.NotNil(t, check(1000000000000, "", send))
+	require.NotNil(t, check(100, "", bankx.NewMsgSend(from, blocked, dex.NewCetCoins(1), 0)))
+	require.NotNil(t, check(100, "airdrop now", send))
+	require.Nil(t, check(100, "no airdrop", send))
+	require.Nil(t, check(100, "", send, send))
+	sdkErr := check(100, "", send, send, send)
+	require.NotNil(t, sdkErr)
+	require.Equal(t, CodeRejectedByRule, sdkErr.Code())
+
+	// the fees beyond int64 are compared without overflow
+	hugeFee, _ := sdk.NewIntFromString("18446744073709551616") // 2^64
+	tx := auth.NewStdTx([]sdk.Msg{send}, auth.NewStdFee(200000, sdk.NewCoins(sdk.NewCoin(dex.CET, hugeFee))), nil, "")
+	decoder := func([]byte) (sdk.Tx, sdk.Error) { return tx, nil }
+	require.NotPanics(t, func() { sdkErr = filter.PreCheckTx(abci.RequestCheckTx{}, decoder, log.NewNopLogger()) })
+	require.NotNil(t, sdkErr)
+	rule, err := compileRule(Rule{MinFee: 1000000000000, MaxFee: 2000000000000})
+	require.Nil(t, err)
+	require.False(t, rule.match(tx))
+	_, err = compileRule(Rule{MinFee: 1, FeeDenom: "CET"})
+	require.NotNil(t, err)
+	rule, err = compileRule(Rule{MinFee: 1, FeeDenom: "usdt"})
+	require.Nil(t, err)
+	require.False(t, rule.match(tx))
+
+	// invalid rules are not applied
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(`[[rules]]
+memo_regex = "("`), 0644))
+	require.NotNil(t, filter.Reload())
+	require.NotNil(t, check(100, "airdrop now", send))
+
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(`{"rules": []}`), 0644))
+	require.NotNil(t, filter.Reload())
+	require.Nil(t, os.Rename(rulesPath, path.Join(dir, "rules.json")))
+	filter.filePath = path.Join(dir, "rules.json")
+	require.Nil(t, filter.Reload())
+	require.Nil(t, check(100, "airdrop now", send))
+}
+
+func TestLoadRuleFilter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rule_filter")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	require.Nil(t, os.MkdirAll(path.Join(dir, PluginsDir), 0755))
+	rulesPath := path.Join(dir, PluginsDir, "rules.toml")
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(`[[rules]]
+max_msgs = 1`), 0644))
+
+	holder := Holder{logger: log.NewNopLogger()}
+	viperSetHome(dir)
+	holder.LoadPlugins()
+	require.Empty(t, holder.GetStatus().LoadErrors)
+	require.Equal(t, 1, len(holder.GetPlugins()))
+	require.Equal(t, RuleFilterName, holder.GetPlugins()[0].Name())
+
+	// reloading reports invalid rules
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(`[[rules]]
+max_fee = 1
+min_fee = 2`), 0644))
+	holder.LoadPlugins()
+	require.Equal(t, 1, len(holder.GetStatus().LoadErrors))
+	require.Equal(t, rulesPath, holder.GetStatus().LoadErrors[0].Path)
+	require.Equal(t, 1, len(holder.GetPlugins()))
+
+	// the signal reloads the rules when it enables the plugins again
+	holder.togglePlugin()
+	require.Empty(t, holder.GetPlugins())
+	require.Nil(t, ioutil.WriteFile(rulesPath, []byte(`[[rules]]
+max_msgs = 2`), 0644))
+	holder.togglePlugin()
+	require.Empty(t, holder.GetStatus().LoadErrors)
+	require.Equal(t, 1, len(holder.GetPlugins()))
+	require.Equal(t, 2, holder.GetPlugins()[0].(*RuleFilter).rules[0].MaxMsgs)
+}
@@ -4,9 +4,11 @@ cd `dirname $0`
 
 RACE=$(ps -f -p $(ps -f -p $PPID | awk '!/PID/{print $3}') | grep ' -race ')
 
+mkdir -p ./data/plugins
+
 if [[ -z ${RACE} ]]
 then
-    go build --buildmode=plugin -o ./data/plugin.so test_plugin.go
+    go build --buildmode=plugin -o ./data/plugins/test_plugin.so test_plugin.go
 else
-    go build -race --buildmode=plugin -o ./data/plugin.so test_plugin.go
-fi
\ No newline at end of file
+    go build -race --buildmode=plugin -o ./data/plugins/test_plugin.so test_plugin.go
+fi
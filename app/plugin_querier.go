@@ -0,0 +1,70 @@
+package app
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/coinexchain/cet-sdk/modules/asset"
+	"github.com/coinexchain/cet-sdk/modules/authx"
+	"github.com/coinexchain/cet-sdk/modules/market"
+	"github.com/coinexchain/dex/app/plugin"
+)
+
+type pluginStateQuerier struct {
+	app *CetChainApp
+}
+
+var _ plugin.StateQuerier = pluginStateQuerier{}
+
+func (q pluginStateQuerier) GetAccountX(ctx sdk.Context, addr sdk.AccAddress) (authx.AccountX, bool) {
+	return q.app.accountXKeeper.GetAccountX(ctx, addr)
+}
+
+func (q pluginStateQuerier) GetCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
+	return q.app.bankxKeeper.GetCoins(ctx, addr)
+}
+
+func (q pluginStateQuerier) GetFrozenCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
+	return q.app.bankxKeeper.GetFrozenCoins(ctx, addr)
+}
+
+func (q pluginStateQuerier) GetLockedCoins(ctx sdk.Context, addr sdk.AccAddress) authx.LockedCoins {
+	return q.app.bankxKeeper.GetLockedCoins(ctx, addr)
+}
+
+func (q pluginStateQuerier) GetToken(ctx sdk.Context, symbol string) asset.Token {
+	return q.app.assetKeeper.GetToken(ctx, symbol)
+}
+
+func (q pluginStateQuerier) IsTokenForbidden(ctx sdk.Context, symbol string) bool {
+	return q.app.assetKeeper.IsTokenForbidden(ctx, symbol)
+}
+
+func (q pluginStateQuerier) IsForbiddenByTokenIssuer(ctx sdk.Context, symbol string, addr sdk.AccAddress) bool {
+	return q.app.assetKeeper.IsForbiddenByTokenIssuer(ctx, symbol, addr)
+}
+
+func (q pluginStateQuerier) GetMarketInfo(ctx sdk.Context, symbol string) (market.MarketInfo, bool) {
+	info, err := q.app.marketKeeper.GetMarketInfo(ctx, symbol)
+	return info, err == nil
+}
+
+func (q pluginStateQuerier) GetAddressFromAlias(ctx sdk.Context, alias string) (sdk.AccAddress, bool) {
+	addr, ok := q.app.aliasKeeper.GetAddressFromAlias(ctx, alias)
+	return addr, ok
+}
+
+func (q pluginStateQuerier) GetAliasListOfAccount(ctx sdk.Context, addr sdk.AccAddress) []string {
+	return q.app.aliasKeeper.GetAliasListOfAccount(ctx, addr)
+}
+
+// newPluginContext returns a context on a cache of the check state, so the writes to it are discarded
+func (app *CetChainApp) newPluginContext() sdk.Context {
+	ctx := app.NewContext(true, abci.Header{
+		Height: app.LastBlockHeight() + 1,
+		Time:   time.Unix(app.currBlockTime, 0),
+	})
+	return ctx.WithMultiStore(ctx.MultiStore().CacheMultiStore())
+}
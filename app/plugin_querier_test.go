@@ -0,0 +1,134 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+	"github.com/coinexchain/dex/app/plugin"
+)
+
+// rejects the txs whose signers have less CET than minBalance
+type balanceFilter struct {
+	minBalance int64
+}
+
+func (f *balanceFilter) Name() string {
+	return "BalanceFilter"
+}
+
+func (f *balanceFilter) PreCheckTx(abci.RequestCheckTx, sdk.TxDecoder, log.Logger) sdk.Error {
+	panic("PreCheckTxWithState should be called instead")
+}
+
+func (f *balanceFilter) PreCheckTxWithState(ctx sdk.Context, q plugin.StateQuerier, req abci.RequestCheckTx,
+	txDecoder sdk.TxDecoder, logger log.Logger) sdk.Error {
+
+	tx, err := txDecoder(req.Tx)
+	if err != nil {
+		return err
+	}
+	for _, signer := range tx.(auth.StdTx).GetSigners() {
+		if q.GetCoins(ctx, signer).AmountOf(dex.CET).Int64() < f.minBalance {
+			return sdk.ErrInsufficientCoins("balance too low")
+		}
+	}
+	return nil
+}
+
+func TestStatefulPlugin(t *testing.T) {
+	toAddr := sdk.AccAddress([]byte("addr"))
+	key, _, fromAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	app := initAppWithBaseAccounts(acc0)
+	// the check state is set up by Commit
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: testChainID, Height: 1, Time: time.Now()}})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	msg := bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(1000000000), time.Now().Unix()+10000)
+	tx := newStdTxBuilder().
+		Msgs(msg).GasAndFee(1000000, 100).AccNumSeqKey(0, 0, key).Build()
+
+	filter := &balanceFilter{minBalance: 40000000000}
+	require.Nil(t, app.RegisterPlugin(filter))
+	require.NotNil(t, app.RegisterPlugin(filter))
+	require.Equal(t, sdk.CodeInsufficientCoins, app.Check(tx).Code)
+
+	filter.minBalance = 20000000000
+	require.Equal(t, sdk.CodeOK, app.Check(tx).Code)
+}
+
+// keeps reading the balances of the signers until the reads are rejected
+type slowReader struct {
+	stopped chan interface{}
+}
+
+func (r *slowReader) Name() string {
+	return "SlowReader"
+}
+
+func (r *slowReader) PreCheckTx(abci.RequestCheckTx, sdk.TxDecoder, log.Logger) sdk.Error {
+	panic("PreCheckTxWithState should be called instead")
+}
+
+func (r *slowReader) PreCheckTxWithState(ctx sdk.Context, q plugin.StateQuerier, req abci.RequestCheckTx,
+	txDecoder sdk.TxDecoder, logger log.Logger) sdk.Error {
+
+	defer func() {
+		r.stopped <- recover()
+	}()
+	tx, err := txDecoder(req.Tx)
+	if err != nil {
+		return err
+	}
+	for {
+		for _, signer := range tx.(auth.StdTx).GetSigners() {
+			q.GetCoins(ctx, signer)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// run with -race, the abandoned call must not read the check state while it is committed
+func TestStatefulPluginAfterDeadline(t *testing.T) {
+	toAddr := sdk.AccAddress([]byte("addr"))
+	key, _, fromAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	app := initAppWithBaseAccounts(acc0)
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: testChainID, Height: 1, Time: time.Now()}})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	msg := bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(1000000000), time.Now().Unix()+10000)
+	tx := newStdTxBuilder().
+		Msgs(msg).GasAndFee(1000000, 100).AccNumSeqKey(0, 0, key).Build()
+
+	reader := &slowReader{stopped: make(chan interface{}, 1)}
+	app.SetCallPolicy(20*time.Millisecond, 100)
+	require.Nil(t, app.RegisterPlugin(reader))
+	require.Equal(t, sdk.CodeOK, app.Check(tx).Code)
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: testChainID, Height: 2, Time: time.Now()}})
+	require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	app.Commit()
+
+	select {
+	case r := <-reader.stopped:
+		require.NotNil(t, r)
+		require.Contains(t, r.(error).Error(), "used after the call")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the abandoned call is not stopped")
+	}
+}
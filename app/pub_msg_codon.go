@@ -0,0 +1,111 @@
+package app
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/coinexchain/dex/app/pubmsg"
+)
+
+// notificationPayloadTypes are the notifications which are encoded by codon with SinkEncodingCodon
+var notificationPayloadTypes = func() map[string]reflect.Type {
+	res := make(map[string]reflect.Type, len(currentNotificationSchemas))
+	for _, s := range currentNotificationSchemas {
+		res[s.Key] = s.Type
+	}
+	return res
+}()
+
+// encodeCodonPayload returns nil if payload is not the current notification of key, or it can not be encoded
+func encodeCodonPayload(key string, payload interface{}) []byte {
+	if t, ok := notificationPayloadTypes[key]; !ok || reflect.TypeOf(payload) != t {
+		return nil
+	}
+	bz, err := pubmsg.EncodePayload(payload)
+	if err != nil {
+		return nil
+	}
+	return bz
+}
+
+// toBinaryPubMsg uses the codon payload encoded by the PubMsg queue. The other msgs, and the notifications
+// without it, such as the ones replayed from the outbox, are kept as JSON.
+func toBinaryPubMsg(msg PubMsg) pubmsg.Msg {
+	if msg.binary != nil {
+		return pubmsg.Msg{Key: string(msg.Key), Format: pubmsg.FormatCodon, Value: msg.binary}
+	}
+	return pubmsg.Msg{Key: string(msg.Key), Format: pubmsg.FormatJSON, Value: msg.Value}
+}
+
+// encodePubMsgs encodes msgs into the stream of a file or unix socket sink
+func encodePubMsgs(msgs []PubMsg, encoding SinkEncoding) []byte {
+	if encoding == SinkEncodingCodon {
+		frames, _ := encodeMsgFrames(msgs)
+		return frames
+	}
+	return encodePubMsgLines(msgs)
+}
+
+// blockEncodings caches the encodings of a block, so that each of them is built only once,
+// however many sinks and clients write the block
+type blockEncodings struct {
+	linesOnce  sync.Once
+	lines      []byte
+	textsOnce  sync.Once
+	texts      [][]byte
+	framesOnce sync.Once
+	frames     []byte
+	msgFrames  [][]byte
+}
+
+// encode returns the stream of block in encoding, which is written to a file or a unix socket
+func (block PubMsgBlock) encode(encoding SinkEncoding) []byte {
+	e := block.encodings
+	if e == nil {
+		return encodePubMsgs(block.Msgs, encoding)
+	}
+	if encoding == SinkEncodingCodon {
+		e.framesOnce.Do(func() { e.frames, e.msgFrames = encodeMsgFrames(block.Msgs) })
+		return e.frames
+	}
+	e.linesOnce.Do(func() { e.lines = encodePubMsgLines(block.Msgs) })
+	return e.lines
+}
+
+// websocketMessages returns the messages of block sent to a websocket client, which are
+// the frames of the msgs with SinkEncodingCodon, and "key#value" otherwise
+func (block PubMsgBlock) websocketMessages(encoding SinkEncoding) [][]byte {
+	e := block.encodings
+	if e == nil {
+		e = &blockEncodings{}
+	}
+	if encoding == SinkEncodingCodon {
+		e.framesOnce.Do(func() { e.frames, e.msgFrames = encodeMsgFrames(block.Msgs) })
+		return e.msgFrames
+	}
+	e.textsOnce.Do(func() {
+		e.texts = make([][]byte, len(block.Msgs))
+		for i, msg := range block.Msgs {
+			bz := make([]byte, 0, len(msg.Key)+len(msg.Value)+1)
+			e.texts[i] = append(append(append(bz, msg.Key...), '#'), msg.Value...)
+		}
+	})
+	return e.texts
+}
+
+// encodeMsgFrames returns the frames of msgs, and the frame of each msg, which shares the memory
+func encodeMsgFrames(msgs []PubMsg) ([]byte, [][]byte) {
+	var buf []byte
+	ends := make([]int, len(msgs))
+	for i, msg := range msgs {
+		buf = pubmsg.AppendFrame(buf, toBinaryPubMsg(msg))
+		ends[i] = len(buf)
+	}
+	frames := make([][]byte, len(msgs))
+	start := 0
+	for i, end := range ends {
+		frames[i] = buf[start:end:end]
+		start = end
+	}
+	return buf, frames
+}
@@ -0,0 +1,282 @@
+package app
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// FlagPubMsgOutboxRetention (or the key in app.toml) is the number of latest heights kept in
+// the outbox, 0 disables the outbox
+const FlagPubMsgOutboxRetention = "pubmsg-outbox-retention"
+
+const (
+	DefaultPubMsgOutboxRetention = 100000
+	PubMsgOutboxName             = "pubmsg-outbox"
+)
+
+// the admin routes to replay the outbox of the running node, which locks the LevelDB
+const (
+	// RoutePubMsgOutbox gets the PubMsgOutboxRange
+	RoutePubMsgOutbox = "/pubmsg-outbox"
+	// RoutePubMsgOutboxBlocks gets the PubMsgBlocks of the heights in [from, to] like Replay,
+	// at most MaxPubMsgOutboxBlocks of them
+	RoutePubMsgOutboxBlocks = "/pubmsg-outbox/{from}/{to}"
+	MaxPubMsgOutboxBlocks   = 100
+)
+
+var errPubMsgOutboxClosed = errors.New("the outbox is closed")
+
+// PubMsgOutboxRange is the first and the last heights in the outbox, which are 0 if it is empty
+type PubMsgOutboxRange struct {
+	First int64 `json:"first"`
+	Last  int64 `json:"last"`
+}
+
+// PubMsgOutbox keeps the PubMsgs of the latest heights in a LevelDB under the data dir, so that
+// the msgs which the consumers missed can be replayed. The "commit" msgs are not stored, and neither
+// are the codon payloads, so the replayed notifications are JSON frames in the codon sinks.
+type PubMsgOutbox struct {
+	db        dbm.DB
+	cdc       *codec.Codec
+	retention int64
+	// the lowest height kept, which is where the pruning starts, 0 if it is not known yet.
+	// It keeps the pruning from walking through the tombstones of the pruned heights.
+	first int64
+	// the admin routes and the PubMsg queue check it, since the node may close the outbox meanwhile
+	mtx    sync.RWMutex
+	closed bool
+}
+
+// PubMsgOutboxDir returns the dir of the outbox under the home dir of cetd
+func PubMsgOutboxDir(rootDir string) string {
+	return filepath.Join(rootDir, "data")
+}
+
+// OpenPubMsgOutbox opens the outbox in dir, the heights before the latest retention ones are pruned in Save
+func OpenPubMsgOutbox(dir string, retention int64) (outbox *PubMsgOutbox, err error) {
+	// NewDB panics if LevelDB can not be opened, such as when it is locked by a running node
+	defer func() {
+		if r := recover(); r != nil {
+			outbox, err = nil, fmt.Errorf("open %s failed: %v", PubMsgOutboxName, r)
+		}
+	}()
+	db := dbm.NewDB(PubMsgOutboxName, dbm.GoLevelDBBackend, dir)
+	return &PubMsgOutbox{db: db, cdc: codec.New(), retention: retention}, nil
+}
+
+func pubMsgOutboxKey(height int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+	return key
+}
+
+// whileOpen calls f if the outbox is not closed, which is not closed until f returns
+func (o *PubMsgOutbox) whileOpen(f func() error) error {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+	if o.closed {
+		return errPubMsgOutboxClosed
+	}
+	return f()
+}
+
+// Save stores the msgs of height, and prunes the heights out of retention. It is called by the
+// PubMsg queue in the background, and does not fsync, since a lost height can be backfilled.
+// Nothing is stored after the outbox is closed.
+func (o *PubMsgOutbox) Save(height int64, msgs []PubMsg) {
+	_ = o.whileOpen(func() error {
+		o.save(height, msgs)
+		return nil
+	})
+}
+
+func (o *PubMsgOutbox) save(height int64, msgs []PubMsg) {
+	if msgs == nil {
+		msgs = []PubMsg{}
+	}
+	batch := o.db.NewBatch()
+	defer batch.Close()
+	batch.Set(pubMsgOutboxKey(height), o.cdc.MustMarshalBinaryBare(msgs))
+	if low := height - o.retention + 1; o.retention > 0 && low > 1 {
+		if o.first == 0 {
+			o.first, _ = o.Range()
+		}
+		if o.first < low {
+			iter := o.db.Iterator(pubMsgOutboxKey(o.first), pubMsgOutboxKey(low))
+			for ; iter.Valid(); iter.Next() {
+				batch.Delete(iter.Key())
+			}
+			iter.Close()
+			o.first = low
+		}
+	}
+	batch.Write()
+}
+
+// Load returns the msgs of height, ok is false if the height is not in the outbox
+func (o *PubMsgOutbox) Load(height int64) (msgs []PubMsg, ok bool) {
+	bz := o.db.Get(pubMsgOutboxKey(height))
+	if bz == nil {
+		return nil, false
+	}
+	o.cdc.MustUnmarshalBinaryBare(bz, &msgs)
+	return msgs, true
+}
+
+// Range returns the first and the last heights in the outbox, which are 0 if it is empty
+func (o *PubMsgOutbox) Range() (first, last int64) {
+	iter := o.db.Iterator(nil, nil)
+	if iter.Valid() {
+		first = int64(binary.BigEndian.Uint64(iter.Key()))
+	}
+	iter.Close()
+	iter = o.db.ReverseIterator(nil, nil)
+	if iter.Valid() {
+		last = int64(binary.BigEndian.Uint64(iter.Key()))
+	}
+	iter.Close()
+	return
+}
+
+// Replay passes the blocks of the heights in [from, to] to send in the original order, and the msgs of
+// each block end with the "commit" msg. It fails before sending anything if a height is missing.
+func (o *PubMsgOutbox) Replay(from, to int64, send func(block PubMsgBlock) error) error {
+	if from <= 0 || from > to {
+		return fmt.Errorf("invalid height range [%d, %d]", from, to)
+	}
+	for h := from; h <= to; h++ {
+		if !o.db.Has(pubMsgOutboxKey(h)) {
+			first, last := o.Range()
+			return fmt.Errorf("height %d is not in the outbox, which has the heights in [%d, %d]", h, first, last)
+		}
+	}
+	for h := from; h <= to; h++ {
+		msgs, _ := o.Load(h)
+		msgs = append(msgs, PubMsg{Key: []byte("commit"), Value: []byte("{}")})
+		if err := send(PubMsgBlock{Height: h, Msgs: msgs}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *PubMsgOutbox) Close() {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if !o.closed {
+		o.closed = true
+		o.db.Close()
+	}
+}
+
+// PubMsgOutboxBlocksRoute fills the heights into RoutePubMsgOutboxBlocks
+func PubMsgOutboxBlocksRoute(from, to int64) string {
+	return fmt.Sprintf("/pubmsg-outbox/%d/%d", from, to)
+}
+
+// ReplayPubMsgOutboxByAdmin is Replay of the outbox of the running node, which is sent by its admin server.
+// It fails before sending anything if a height is not in the outbox when it starts.
+func ReplayPubMsgOutboxByAdmin(client *admin.Client, from, to int64, send func(block PubMsgBlock) error) error {
+	if from <= 0 || from > to {
+		return fmt.Errorf("invalid height range [%d, %d]", from, to)
+	}
+	var r PubMsgOutboxRange
+	if err := client.Get(RoutePubMsgOutbox, &r); err != nil {
+		return err
+	}
+	if from < r.First || to > r.Last {
+		return fmt.Errorf("the heights in [%d, %d] are not all in the outbox, which has the heights in [%d, %d]",
+			from, to, r.First, r.Last)
+	}
+	for start := from; start <= to; start += MaxPubMsgOutboxBlocks {
+		end := start + MaxPubMsgOutboxBlocks - 1
+		if end > to {
+			end = to
+		}
+		var blocks []PubMsgBlock
+		if err := client.Get(PubMsgOutboxBlocksRoute(start, end), &blocks); err != nil {
+			return err
+		}
+		for _, block := range blocks {
+			if err := send(block); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (app *CetChainApp) registerPubMsgOutboxRoutes(r *mux.Router) {
+	r.HandleFunc(RoutePubMsgOutbox, func(w http.ResponseWriter, _ *http.Request) {
+		if app.pubMsgOutbox == nil {
+			admin.WriteError(w, http.StatusNotFound, errors.New("the outbox is disabled"))
+			return
+		}
+		var res PubMsgOutboxRange
+		err := app.pubMsgOutbox.whileOpen(func() error {
+			res.First, res.Last = app.pubMsgOutbox.Range()
+			return nil
+		})
+		if err != nil {
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		admin.WriteJSON(w, res)
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc(RoutePubMsgOutboxBlocks, func(w http.ResponseWriter, r *http.Request) {
+		if app.pubMsgOutbox == nil {
+			admin.WriteError(w, http.StatusNotFound, errors.New("the outbox is disabled"))
+			return
+		}
+		from, err1 := strconv.ParseInt(mux.Vars(r)["from"], 10, 64)
+		to, err2 := strconv.ParseInt(mux.Vars(r)["to"], 10, 64)
+		if err1 != nil || err2 != nil || from <= 0 || from > to || to-from >= MaxPubMsgOutboxBlocks {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid height range [%s, %s], "+
+				"at most %d heights can be replayed at a time", mux.Vars(r)["from"], mux.Vars(r)["to"], MaxPubMsgOutboxBlocks))
+			return
+		}
+		blocks := make([]PubMsgBlock, 0, to-from+1)
+		err := app.pubMsgOutbox.whileOpen(func() error {
+			return app.pubMsgOutbox.Replay(from, to, func(block PubMsgBlock) error {
+				blocks = append(blocks, block)
+				return nil
+			})
+		})
+		if err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		admin.WriteJSON(w, blocks)
+	}).Methods(http.MethodGet)
+}
+
+// initPubMsgOutbox opens the outbox if the PubMsg stream is produced
+func (app *CetChainApp) initPubMsgOutbox() {
+	retention := int64(DefaultPubMsgOutboxRetention)
+	if viper.IsSet(FlagPubMsgOutboxRetention) {
+		retention = viper.GetInt64(FlagPubMsgOutboxRetention)
+	}
+	if !app.msgQueProducer.IsOpenToggle() || retention <= 0 {
+		return
+	}
+	outbox, err := OpenPubMsgOutbox(PubMsgOutboxDir(viper.GetString(cli.HomeFlag)), retention)
+	if err != nil {
+		panic(err)
+	}
+	app.pubMsgOutbox = outbox
+}
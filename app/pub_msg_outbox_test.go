@@ -0,0 +1,191 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/dex/app/admin"
+)
+
+func replayKeys(t *testing.T, outbox *PubMsgOutbox, from, to int64) []string {
+	var keys []string
+	err := outbox.Replay(from, to, func(block PubMsgBlock) error {
+		for _, msg := range block.Msgs {
+			keys = append(keys, string(msg.Key))
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	return keys
+}
+
+func TestPubMsgOutbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	outbox, err := OpenPubMsgOutbox(dir, 3)
+	require.Nil(t, err)
+	first, last := outbox.Range()
+	require.Equal(t, int64(0), first)
+	require.Equal(t, int64(0), last)
+
+	outbox.Save(1, newPubMsgBlock(1, "a", "b").Msgs)
+	outbox.Save(2, nil)
+	outbox.Save(3, newPubMsgBlock(3, "c").Msgs)
+	msgs, ok := outbox.Load(1)
+	require.True(t, ok)
+	require.Equal(t, newPubMsgBlock(1, "a", "b").Msgs, msgs)
+	require.Equal(t, []string{"a", "b", "commit", "commit", "c", "commit"}, replayKeys(t, outbox, 1, 3))
+
+	// only the latest 3 heights are kept
+	outbox.Save(4, newPubMsgBlock(4, "d").Msgs)
+	outbox.Save(5, newPubMsgBlock(5, "e").Msgs)
+	_, ok = outbox.Load(2)
+	require.False(t, ok)
+	first, last = outbox.Range()
+	require.Equal(t, int64(3), first)
+	require.Equal(t, int64(5), last)
+	// the pruning starts from the lowest height kept
+	require.Equal(t, int64(3), outbox.first)
+	outbox.Save(6, newPubMsgBlock(6, "f").Msgs)
+	first, last = outbox.Range()
+	require.Equal(t, int64(4), first)
+	require.Equal(t, int64(6), last)
+
+	// nothing is sent if a height is missing
+	var sent int
+	err = outbox.Replay(2, 5, func(block PubMsgBlock) error {
+		sent++
+		return nil
+	})
+	require.NotNil(t, err)
+	require.Equal(t, 0, sent)
+	require.NotNil(t, outbox.Replay(5, 4, func(block PubMsgBlock) error { return nil }))
+
+	// the outbox can not be opened twice
+	_, err = OpenPubMsgOutbox(dir, 0)
+	require.NotNil(t, err)
+	outbox.Close()
+
+	outbox, err = OpenPubMsgOutbox(dir, 0)
+	require.Nil(t, err)
+	defer outbox.Close()
+	require.Equal(t, []string{"d", "commit", "e", "commit"}, replayKeys(t, outbox, 4, 5))
+}
+
+func TestPubMsgOutboxOfApp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	app := initAppWithBaseAccounts()
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.pubMsgOutbox, err = OpenPubMsgOutbox(dir, 10)
+	require.Nil(t, err)
+	defer app.pubMsgOutbox.Close()
+
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+	app.pubMsgQueue.flush()
+
+	keys := replayKeys(t, app.pubMsgOutbox, 1, 1)
+	require.Equal(t, "height_info", keys[0])
+	require.Equal(t, "commit", keys[len(keys)-1])
+}
+
+func TestPubMsgOutboxDrainedOnClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	app := initAppWithBaseAccounts()
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.pubMsgOutbox, err = OpenPubMsgOutbox(dir, 10)
+	require.Nil(t, err)
+	// nobody reads the sink until Close, so the blocks wait in the queue
+	sink, c := NewChanSink("test", 0)
+	require.Nil(t, app.AddPubMsgSink(sink, SinkPolicyBlock, 0))
+
+	for h := int64(1); h <= 3; h++ {
+		header := abci.Header{Height: h, Time: time.Now(), ChainID: testChainID}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{Height: h})
+		app.Commit()
+	}
+
+	var heights []int64
+	read := make(chan struct{})
+	go func() {
+		for block := range c {
+			heights = append(heights, block.Height)
+		}
+		close(read)
+	}()
+	app.Close()
+	<-read
+	require.Equal(t, []int64{1, 2, 3}, heights)
+	require.Equal(t, int64(3), app.GetPubMsgQueueStatus().SentHeight)
+
+	// the outbox has all the blocks in the queue, and is closed with the app
+	outbox, err := OpenPubMsgOutbox(dir, 10)
+	require.Nil(t, err)
+	defer outbox.Close()
+	first, last := outbox.Range()
+	require.Equal(t, int64(1), first)
+	require.Equal(t, int64(3), last)
+	require.Equal(t, "height_info", replayKeys(t, outbox, 3, 3)[0])
+}
+
+func TestReplayPubMsgOutboxByAdmin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	app := initAppWithBaseAccounts()
+	server := admin.NewServer(log.NewNopLogger())
+	app.registerPubMsgOutboxRoutes(server.Router())
+	sockPath := path.Join(dir, admin.SocketFile)
+	require.Nil(t, server.Start(sockPath))
+	client := admin.NewClient(sockPath)
+	var keys []string
+	send := func(block PubMsgBlock) error {
+		for _, msg := range block.Msgs {
+			keys = append(keys, string(msg.Key))
+		}
+		return nil
+	}
+
+	// the outbox is disabled
+	require.NotNil(t, ReplayPubMsgOutboxByAdmin(client, 1, 1, send))
+
+	app.pubMsgOutbox, err = OpenPubMsgOutbox(dir, 0)
+	require.Nil(t, err)
+	for h := int64(1); h <= MaxPubMsgOutboxBlocks+2; h++ {
+		app.pubMsgOutbox.Save(h, newPubMsgBlock(h, "a").Msgs)
+	}
+	require.Nil(t, ReplayPubMsgOutboxByAdmin(client, 2, MaxPubMsgOutboxBlocks+2, send))
+	require.Equal(t, 2*(MaxPubMsgOutboxBlocks+1), len(keys))
+	require.Equal(t, []string{"a", "commit"}, keys[:2])
+
+	keys = nil
+	require.NotNil(t, ReplayPubMsgOutboxByAdmin(client, 2, MaxPubMsgOutboxBlocks+3, send))
+	require.NotNil(t, ReplayPubMsgOutboxByAdmin(client, 2, 1, send))
+	require.NotNil(t, client.Get(PubMsgOutboxBlocksRoute(1, MaxPubMsgOutboxBlocks+1), &[]PubMsgBlock{}))
+	require.Empty(t, keys)
+
+	// the outbox closed by the node is not read any more
+	app.pubMsgOutbox.Close()
+	require.NotNil(t, ReplayPubMsgOutboxByAdmin(client, 1, 1, send))
+	app.pubMsgOutbox.Save(MaxPubMsgOutboxBlocks+3, nil)
+}
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// FlagPubMsgQueueSize (or the key in app.toml) is the number of committed blocks whose PubMsgs can wait
+// for the background worker, which encodes them and sends them to the msgqueue brokers and the sinks,
+// in the order of heights, and saves them in the outbox. Commit waits when the queue is full, and 0 makes
+// Commit do the work. The queue is drained when the node is stopped, but the blocks still in it when the
+// node is killed are not in the outbox, use backfill-msgs for them.
+const FlagPubMsgQueueSize = "pubmsg-queue-size"
+
+const DefaultPubMsgQueueSize = 100 // blocks
+
+// RoutePubMsgQueue is the admin route to get PubMsgQueueStatus
+const RoutePubMsgQueue = "/pubmsg-queue"
+
+// PubMsgQueueStatus is the metrics of the PubMsg queue, whose lag behind the chain is LagBlocks
+type PubMsgQueueStatus struct {
+	Capacity int `json:"capacity"`
+	// the last height pushed to the queue by Commit, and the last one whose msgs are all sent
+	CommittedHeight int64 `json:"committed_height"`
+	SentHeight      int64 `json:"sent_height"`
+	LagBlocks       int64 `json:"lag_blocks"`
+	// how long the oldest block in the queue has waited since it was committed
+	LagSeconds float64 `json:"lag_seconds"`
+	// how many times Commit waited for the full queue, and how long it waited in total
+	BlockedCommits int64   `json:"blocked_commits"`
+	BlockedSeconds float64 `json:"blocked_seconds"`
+}
+
+// pubMsgBlock holds the msgs of a committed block, which may not be encoded yet
+type pubMsgBlock struct {
+	height         int64
+	msgs           []PubMsg
+	failedTxEvents []PubMsg
+	// set for a flush, which is closed when all the blocks before it are sent
+	flushed chan struct{}
+}
+
+// pubMsgQueue passes the blocks to a single worker, so they are sent in the order of heights
+type pubMsgQueue struct {
+	blocks   chan pubMsgBlock // nil if the blocks are sent by Commit
+	send     func(block pubMsgBlock)
+	capacity int
+	logger   log.Logger
+
+	mtx             sync.Mutex
+	committedHeight int64
+	sentHeight      int64
+	committedTimes  []time.Time // of the blocks in the queue
+	blockedCommits  int64
+	blockedTime     time.Duration
+	// set by close, the worker is stopped then
+	closed bool
+	done   chan struct{}
+}
+
+func newPubMsgQueue(capacity int, send func(block pubMsgBlock), logger log.Logger) *pubMsgQueue {
+	q := &pubMsgQueue{send: send, capacity: capacity, logger: logger}
+	if capacity > 0 {
+		q.blocks = make(chan pubMsgBlock, capacity)
+		q.done = make(chan struct{})
+		go q.loop()
+	}
+	return q
+}
+
+func (q *pubMsgQueue) loop() {
+	for block := range q.blocks {
+		if block.flushed != nil {
+			close(block.flushed)
+			continue
+		}
+		q.run(block)
+	}
+	close(q.done)
+}
+
+func (q *pubMsgQueue) run(block pubMsgBlock) {
+	q.send(block)
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.sentHeight = block.height
+	q.committedTimes = q.committedTimes[1:]
+}
+
+// push waits if the queue is full, so that the lag is bounded
+func (q *pubMsgQueue) push(block pubMsgBlock) {
+	q.mtx.Lock()
+	if q.closed {
+		q.mtx.Unlock()
+		q.logger.Error(fmt.Sprintf("PubMsg queue is closed, msgs of height %d are dropped", block.height))
+		return
+	}
+	q.committedHeight = block.height
+	q.committedTimes = append(q.committedTimes, time.Now())
+	q.mtx.Unlock()
+	if q.blocks == nil {
+		q.run(block)
+		return
+	}
+	select {
+	case q.blocks <- block:
+		return
+	default:
+	}
+	q.logger.Info(fmt.Sprintf("PubMsg queue is full, commit of height %d waits", block.height))
+	start := time.Now()
+	q.blocks <- block
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.blockedCommits++
+	q.blockedTime += time.Since(start)
+}
+
+// flush waits until the blocks pushed so far are sent
+func (q *pubMsgQueue) flush() {
+	if q.blocks == nil || q.isClosed() {
+		return
+	}
+	flushed := make(chan struct{})
+	q.blocks <- pubMsgBlock{flushed: flushed}
+	<-flushed
+}
+
+// close waits until the blocks in the queue are sent, and stops the worker. The blocks pushed
+// later are dropped. It must not be called concurrently with push. It returns false if the worker
+// is still sending after sinkCloseTimeout, which is stuck by a sink with SinkPolicyBlock.
+func (q *pubMsgQueue) close() bool {
+	q.mtx.Lock()
+	closed := q.closed
+	q.closed = true
+	q.mtx.Unlock()
+	if closed || q.blocks == nil {
+		return true
+	}
+	close(q.blocks)
+	timer := time.NewTimer(sinkCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-q.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (q *pubMsgQueue) isClosed() bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.closed
+}
+
+func (q *pubMsgQueue) status() PubMsgQueueStatus {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	res := PubMsgQueueStatus{
+		Capacity:        q.capacity,
+		CommittedHeight: q.committedHeight,
+		SentHeight:      q.sentHeight,
+		LagBlocks:       int64(len(q.committedTimes)),
+		BlockedCommits:  q.blockedCommits,
+		BlockedSeconds:  q.blockedTime.Seconds(),
+	}
+	if len(q.committedTimes) != 0 {
+		res.LagSeconds = time.Since(q.committedTimes[0]).Seconds()
+	}
+	return res
+}
+
+func (app *CetChainApp) initPubMsgQueue() {
+	capacity := DefaultPubMsgQueueSize
+	if viper.IsSet(FlagPubMsgQueueSize) {
+		capacity = viper.GetInt(FlagPubMsgQueueSize)
+	}
+	app.pubMsgQueue = newPubMsgQueue(capacity, app.sendPubMsgBlock, app.Logger())
+}
+
+// pushPubMsgBlock hands the msgs of the block being committed to the queue
+func (app *CetChainApp) pushPubMsgBlock() {
+	// app.pubMsgs is reused by the next block
+	msgs := make([]PubMsg, len(app.pubMsgs), len(app.pubMsgs)+1)
+	copy(msgs, app.pubMsgs)
+	app.pubMsgQueue.push(pubMsgBlock{height: app.height, msgs: msgs, failedTxEvents: app.failedTxEvents})
+	app.failedTxEvents = nil
+}
+
+// sendPubMsgBlock runs in the worker of the queue, or in Commit if the queue size is 0
+func (app *CetChainApp) sendPubMsgBlock(block pubMsgBlock) {
+	encodePubMsgsInPlace(block.msgs, app.pubMsgSinks.WantsCodon())
+	encodePubMsgsInPlace(block.failedTxEvents, app.failedTxEventSinks.WantsCodon())
+	if app.pubMsgOutbox != nil {
+		app.pubMsgOutbox.Save(block.height, block.msgs)
+	}
+	for _, msg := range block.msgs {
+		app.msgQueProducer.SendMsg(msg.Key, msg.Value)
+	}
+	app.msgQueProducer.SendMsg([]byte("commit"), []byte("{}"))
+	app.sendPubMsgsToSinks(block.height, block.msgs)
+	app.sendFailedTxEventsToSinks(block.height, block.failedTxEvents)
+}
+
+// encodePubMsgsInPlace encodes the notifications to JSON, and also to codon if withCodon is true
+func encodePubMsgsInPlace(msgs []PubMsg, withCodon bool) {
+	for i := range msgs {
+		msgs[i] = msgs[i].encodedWith(withCodon)
+	}
+}
+
+// GetPubMsgQueueStatus returns the metrics of the PubMsg queue
+func (app *CetChainApp) GetPubMsgQueueStatus() PubMsgQueueStatus {
+	return app.pubMsgQueue.status()
+}
+
+func (app *CetChainApp) registerPubMsgQueueRoutes(r *mux.Router) {
+	r.HandleFunc(RoutePubMsgQueue, func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, app.GetPubMsgQueueStatus())
+	}).Methods(http.MethodGet)
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+)
+
+func TestPubMsgQueue(t *testing.T) {
+	release := make(chan struct{})
+	var sent []int64
+	q := newPubMsgQueue(2, func(block pubMsgBlock) {
+		<-release
+		sent = append(sent, block.height)
+	}, log.NewNopLogger())
+
+	q.push(pubMsgBlock{height: 1})
+	// the worker is waiting for block 1, while 2 and 3 fill the queue
+	waitUntil(t, func() bool { return len(q.blocks) == 0 })
+	q.push(pubMsgBlock{height: 2})
+	q.push(pubMsgBlock{height: 3})
+	status := q.status()
+	require.Equal(t, PubMsgQueueStatus{Capacity: 2, CommittedHeight: 3, LagBlocks: 3,
+		LagSeconds: status.LagSeconds}, status)
+	require.True(t, status.LagSeconds > 0)
+
+	pushed := make(chan struct{})
+	go func() {
+		q.push(pubMsgBlock{height: 4})
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push should wait for the full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-pushed
+	q.flush()
+	require.Equal(t, []int64{1, 2, 3, 4}, sent)
+	status = q.status()
+	require.Equal(t, int64(4), status.SentHeight)
+	require.Equal(t, int64(0), status.LagBlocks)
+	require.Equal(t, 0.0, status.LagSeconds)
+	require.Equal(t, int64(1), status.BlockedCommits)
+	require.True(t, status.BlockedSeconds > 0)
+}
+
+func TestSyncPubMsgQueue(t *testing.T) {
+	var sent []int64
+	q := newPubMsgQueue(0, func(block pubMsgBlock) {
+		sent = append(sent, block.height)
+	}, log.NewNopLogger())
+	q.push(pubMsgBlock{height: 1})
+	require.Equal(t, []int64{1}, sent)
+	q.flush()
+	require.Equal(t, int64(1), q.status().SentHeight)
+}
+
+func TestClosePubMsgQueue(t *testing.T) {
+	release := make(chan struct{})
+	var sent []int64
+	q := newPubMsgQueue(2, func(block pubMsgBlock) {
+		<-release
+		sent = append(sent, block.height)
+	}, log.NewNopLogger())
+	q.push(pubMsgBlock{height: 1})
+	q.push(pubMsgBlock{height: 2})
+	close(release)
+	q.close()
+	require.Equal(t, []int64{1, 2}, sent)
+
+	// the blocks pushed after close are dropped
+	q.push(pubMsgBlock{height: 3})
+	q.flush()
+	q.close()
+	require.Equal(t, []int64{1, 2}, sent)
+	require.Equal(t, int64(2), q.status().CommittedHeight)
+}
+
+func TestPubMsgsEncodedByQueue(t *testing.T) {
+	app := initAppWithBaseAccounts()
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	sink, c := NewChanSink("test", 0)
+	require.Nil(t, app.AddPubMsgSink(sink, SinkPolicyBlock, 0))
+
+	encoded := false
+	for h := int64(1); h <= 3; h++ {
+		header := abci.Header{Height: h, Time: time.Now(), ChainID: testChainID}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.appendLazyPubMsg("lazy", func() interface{} {
+			encoded = true
+			return struct{}{}
+		})
+		app.EndBlock(abci.RequestEndBlock{Height: h})
+		// Commit returns before the sink takes the block
+		app.Commit()
+	}
+
+	for h := int64(1); h <= 3; h++ {
+		block := <-c
+		require.Equal(t, h, block.Height)
+		for _, msg := range block.Msgs {
+			require.NotNil(t, msg.Value)
+			require.Nil(t, msg.payload)
+		}
+	}
+	require.True(t, encoded)
+	app.pubMsgQueue.flush()
+	require.Equal(t, int64(3), app.GetPubMsgQueueStatus().SentHeight)
+}
@@ -0,0 +1,420 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// FlagPubMsgSinks (or the key in app.toml) lists the sinks which receive the PubMsg stream, besides
+// the msgqueue brokers. Each one looks like "<type>:<target>[?policy=block|drop|buffer&buffer=<blocks>&encoding=json|codon]":
+//
+//	file:<dir>?segment=<heights>  files in dir, a new one is started every segment heights
+//	unix:<path>                   a unix socket, from which every connected client reads the stream
+//	ws:<host:port>/<path>         a websocket endpoint, to which every connected client is sent the stream
+//	    ?origins=<origin>,...     the pages from which browsers can connect besides the same host, "*" for any
+const FlagPubMsgSinks = "pubmsg-sinks"
+
+const (
+	SinkTypeFile      = "file"
+	SinkTypeUnix      = "unix"
+	SinkTypeWebsocket = "ws"
+)
+
+// SinkPolicy decides what happens to the blocks which a sink can not take in time
+type SinkPolicy string
+
+const (
+	// SinkPolicyBlock makes the PubMsg queue write each block, and retry until it succeeds
+	SinkPolicyBlock SinkPolicy = "block"
+	// SinkPolicyDrop never blocks the PubMsg queue: a block is dropped if the buffer is full or the write fails
+	SinkPolicyDrop SinkPolicy = "drop"
+	// SinkPolicyBuffer never loses a block: failed writes are retried, and the PubMsg queue waits when the buffer is full
+	SinkPolicyBuffer SinkPolicy = "buffer"
+)
+
+// SinkEncoding decides how the msgs are written by a sink
+type SinkEncoding string
+
+const (
+	// SinkEncodingJSON writes the msgs as "key#value", whose values are JSON
+	SinkEncodingJSON SinkEncoding = "json"
+	// SinkEncodingCodon writes the binary frames of package app/pubmsg, in which the notifications are
+	// encoded by codon. It is much more compact for the high-volume consumers, which decode the
+	// stream with app/pubmsg.
+	SinkEncodingCodon SinkEncoding = "codon"
+)
+
+const (
+	DefaultSinkBufferSize = 1000 // blocks
+	maxSinkRetryInterval  = time.Second
+)
+
+// sinkCloseTimeout bounds how long the shutdown waits for a sink which does not take the blocks,
+// such as a ChanSink whose consumer has stopped reading. Such a sink is left unclosed.
+var sinkCloseTimeout = 10 * time.Second
+
+// PubMsgBlock holds the PubMsgs of a committed block, the last of which is the "commit" msg
+type PubMsgBlock struct {
+	Height int64
+	Msgs   []PubMsg
+	// set by PubMsgSinks.Send, so that the sinks share the encodings of the block
+	encodings *blockEncodings
+}
+
+// PubMsgSink receives the PubMsg stream block by block. Except for the ones with SinkPolicyBlock,
+// the sinks are written by their own goroutines, so a block must not be modified after it is sent.
+type PubMsgSink interface {
+	Name() string
+	WriteBlock(block PubMsgBlock) error
+	Close() error
+}
+
+// encodedSink is implemented by the sinks which write the msgs in a SinkEncoding
+type encodedSink interface {
+	Encoding() SinkEncoding
+}
+
+// sinkRunner applies the policy of a sink
+type sinkRunner struct {
+	sink    PubMsgSink
+	policy  SinkPolicy
+	queue   chan PubMsgBlock
+	done    chan struct{}
+	closing int32
+	dropped int64
+	logger  log.Logger
+}
+
+func newSinkRunner(sink PubMsgSink, policy SinkPolicy, bufferSize int, logger log.Logger) *sinkRunner {
+	r := &sinkRunner{sink: sink, policy: policy, done: make(chan struct{}), logger: logger}
+	if policy == SinkPolicyBlock {
+		close(r.done)
+		return r
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultSinkBufferSize
+	}
+	r.queue = make(chan PubMsgBlock, bufferSize)
+	go r.loop()
+	return r
+}
+
+func (r *sinkRunner) send(block PubMsgBlock) {
+	switch r.policy {
+	case SinkPolicyBlock:
+		r.writeUntilOK(block)
+	case SinkPolicyDrop:
+		select {
+		case r.queue <- block:
+		default:
+			r.drop(block, "queue is full")
+		}
+	default:
+		r.queue <- block
+	}
+}
+
+func (r *sinkRunner) loop() {
+	defer close(r.done)
+	for block := range r.queue {
+		if r.policy != SinkPolicyDrop {
+			r.writeUntilOK(block)
+		} else if err := r.sink.WriteBlock(block); err != nil {
+			r.drop(block, err.Error())
+		}
+	}
+}
+
+// writeUntilOK retries with growing intervals, it gives up only when the sink is being closed
+func (r *sinkRunner) writeUntilOK(block PubMsgBlock) {
+	interval := 10 * time.Millisecond
+	for {
+		err := r.sink.WriteBlock(block)
+		if err == nil {
+			return
+		}
+		r.logger.Error(fmt.Sprintf("write block %d to sink %s failed: %s", block.Height, r.sink.Name(), err.Error()))
+		if atomic.LoadInt32(&r.closing) != 0 {
+			r.drop(block, "sink is closed")
+			return
+		}
+		time.Sleep(interval)
+		if interval *= 2; interval > maxSinkRetryInterval {
+			interval = maxSinkRetryInterval
+		}
+	}
+}
+
+func (r *sinkRunner) drop(block PubMsgBlock, reason string) {
+	atomic.AddInt64(&r.dropped, 1)
+	r.logger.Error(fmt.Sprintf("block %d is dropped by sink %s: %s", block.Height, r.sink.Name(), reason))
+}
+
+func (r *sinkRunner) close() error {
+	atomic.StoreInt32(&r.closing, 1)
+	if r.queue != nil {
+		close(r.queue)
+	}
+	timer := time.NewTimer(sinkCloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-r.done:
+		return r.sink.Close()
+	case <-timer.C:
+		// the sink is still being written, closing it may break the writer
+		return fmt.Errorf("the queued blocks are not written in %s", sinkCloseTimeout)
+	}
+}
+
+// PubMsgSinks fans the PubMsg stream out to several sinks
+type PubMsgSinks struct {
+	mtx        sync.RWMutex
+	runners    []*sinkRunner
+	wantsCodon bool
+	logger     log.Logger
+}
+
+func NewPubMsgSinks(logger log.Logger) *PubMsgSinks {
+	return &PubMsgSinks{logger: logger}
+}
+
+// Add starts sending the stream to sink, bufferSize is the number of queued blocks for the non-block policies
+func (s *PubMsgSinks) Add(sink PubMsgSink, policy SinkPolicy, bufferSize int) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.runners = append(s.runners, newSinkRunner(sink, policy, bufferSize, s.logger))
+	if e, ok := sink.(encodedSink); ok && e.Encoding() == SinkEncodingCodon {
+		s.wantsCodon = true
+	}
+	return nil
+}
+
+// WantsCodon is true if any sink writes SinkEncodingCodon, for which the PubMsg queue encodes the notifications by codon
+func (s *PubMsgSinks) WantsCodon() bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.wantsCodon
+}
+
+func (s *PubMsgSinks) Len() int {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return len(s.runners)
+}
+
+// Send passes block to the sinks in the order they are added
+func (s *PubMsgSinks) Send(block PubMsgBlock) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if block.encodings == nil {
+		block.encodings = &blockEncodings{}
+	}
+	for _, r := range s.runners {
+		r.send(block)
+	}
+}
+
+// Dropped returns the number of dropped blocks of each sink
+func (s *PubMsgSinks) Dropped() map[string]int64 {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	res := make(map[string]int64, len(s.runners))
+	for _, r := range s.runners {
+		res[r.sink.Name()] = atomic.LoadInt64(&r.dropped)
+	}
+	return res
+}
+
+// Close flushes the queued blocks and closes all the sinks, it gives up a sink after sinkCloseTimeout
+func (s *PubMsgSinks) Close() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, r := range s.runners {
+		if err := r.close(); err != nil {
+			s.logger.Error(fmt.Sprintf("close sink %s failed: %s", r.sink.Name(), err.Error()))
+		}
+	}
+	s.runners = nil
+}
+
+func (policy SinkPolicy) Validate() error {
+	switch policy {
+	case SinkPolicyBlock, SinkPolicyDrop, SinkPolicyBuffer:
+		return nil
+	}
+	return fmt.Errorf("unknown sink policy: %s", policy)
+}
+
+func (encoding SinkEncoding) Validate() error {
+	switch encoding {
+	case SinkEncodingJSON, SinkEncodingCodon:
+		return nil
+	}
+	return fmt.Errorf("unknown sink encoding: %s", encoding)
+}
+
+// SinkConfig is a parsed item of FlagPubMsgSinks
+type SinkConfig struct {
+	Type       string
+	Target     string
+	Policy     SinkPolicy
+	BufferSize int
+	Encoding   SinkEncoding
+	Params     url.Values
+}
+
+func ParseSinkConfig(cfg string) (SinkConfig, error) {
+	res := SinkConfig{BufferSize: DefaultSinkBufferSize, Encoding: SinkEncodingJSON}
+	idx := strings.Index(cfg, ":")
+	if idx <= 0 {
+		return res, fmt.Errorf("invalid sink config: %s", cfg)
+	}
+	res.Type = cfg[:idx]
+	res.Target = cfg[idx+1:]
+	if idx = strings.Index(res.Target, "?"); idx >= 0 {
+		params, err := url.ParseQuery(res.Target[idx+1:])
+		if err != nil {
+			return res, fmt.Errorf("invalid sink config: %s, %s", cfg, err.Error())
+		}
+		res.Params = params
+		res.Target = res.Target[:idx]
+	}
+	if res.Target == "" {
+		return res, fmt.Errorf("invalid sink config: %s, target is missing", cfg)
+	}
+
+	// writing local files is reliable enough to block on, while remote readers come and go
+	res.Policy = SinkPolicyDrop
+	if res.Type == SinkTypeFile {
+		res.Policy = SinkPolicyBlock
+	}
+	if policy := res.Params.Get("policy"); policy != "" {
+		res.Policy = SinkPolicy(policy)
+	}
+	if err := res.Policy.Validate(); err != nil {
+		return res, err
+	}
+	if size := res.Params.Get("buffer"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 {
+			return res, fmt.Errorf("invalid buffer size of sink: %s", cfg)
+		}
+		res.BufferSize = n
+	}
+	if encoding := res.Params.Get("encoding"); encoding != "" {
+		res.Encoding = SinkEncoding(encoding)
+	}
+	if err := res.Encoding.Validate(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// NewSink creates the sink described by cfg
+func NewSink(cfg SinkConfig, logger log.Logger) (PubMsgSink, error) {
+	switch cfg.Type {
+	case SinkTypeFile:
+		segment := int64(DefaultSegmentHeights)
+		if s := cfg.Params.Get("segment"); s != "" {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid segment of file sink: %s", s)
+			}
+			segment = n
+		}
+		return NewFileSink(cfg.Target, segment, cfg.Encoding)
+	case SinkTypeUnix:
+		return NewUnixSocketSink(cfg.Target, cfg.Encoding, logger)
+	case SinkTypeWebsocket:
+		var origins []string
+		if s := cfg.Params.Get("origins"); s != "" {
+			origins = strings.Split(s, ",")
+		}
+		return NewWebsocketSink(cfg.Target, cfg.Encoding, origins, logger)
+	}
+	return nil, fmt.Errorf("unknown sink type: %s", cfg.Type)
+}
+
+func getPubMsgSinkConfigs() []string {
+	return viper.GetStringSlice(FlagPubMsgSinks)
+}
+
+func (app *CetChainApp) initPubMsgSinks() {
+	app.pubMsgSinks = app.newPubMsgSinks("PubMsg stream", getPubMsgSinkConfigs())
+}
+
+// newPubMsgSinks creates the sinks of a stream from the items of FlagPubMsgSinks or the like
+func (app *CetChainApp) newPubMsgSinks(stream string, items []string) *PubMsgSinks {
+	sinks := NewPubMsgSinks(app.Logger())
+	for _, item := range items {
+		cfg, err := ParseSinkConfig(item)
+		if err != nil {
+			panic(err)
+		}
+		sink, err := NewSink(cfg, app.Logger())
+		if err != nil {
+			panic(fmt.Sprintf("create sink %s failed: %s", item, err.Error()))
+		}
+		if err = sinks.Add(sink, cfg.Policy, cfg.BufferSize); err != nil {
+			panic(err)
+		}
+		app.Logger().Info(fmt.Sprintf("%s is sent to %s", stream, sink.Name()))
+	}
+	return sinks
+}
+
+// AddPubMsgSink lets an embedded consumer, such as a ChanSink, receive the PubMsg stream. The stream is
+// produced only if feature-toggle is on, and the msgqueue brokers or FlagPubMsgSinks are configured.
+// Use "nop" as the broker if nothing but the sinks added here are wanted.
+func (app *CetChainApp) AddPubMsgSink(sink PubMsgSink, policy SinkPolicy, bufferSize int) error {
+	return app.pubMsgSinks.Add(sink, policy, bufferSize)
+}
+
+// sendPubMsgsToSinks is called by the PubMsg queue with the encoded msgs of a block
+func (app *CetChainApp) sendPubMsgsToSinks(height int64, msgs []PubMsg) {
+	if app.pubMsgSinks.Len() == 0 {
+		return
+	}
+	msgs = append(msgs, PubMsg{Key: []byte("commit"), Value: []byte("{}")})
+	app.pubMsgSinks.Send(PubMsgBlock{Height: height, Msgs: msgs})
+}
+
+// ChanSink passes the stream to an embedded consumer through a Go channel
+type ChanSink struct {
+	name string
+	c    chan PubMsgBlock
+}
+
+var _ PubMsgSink = (*ChanSink)(nil)
+
+// NewChanSink returns a sink and the channel from which the consumer reads, the channel is closed with the sink
+func NewChanSink(name string, size int) (*ChanSink, <-chan PubMsgBlock) {
+	c := make(chan PubMsgBlock, size)
+	return &ChanSink{name: name, c: c}, c
+}
+
+func (s *ChanSink) Name() string {
+	return "chan:" + s.name
+}
+
+// WriteBlock waits until the consumer reads the block, the sink's policy decides what happens meanwhile
+func (s *ChanSink) WriteBlock(block PubMsgBlock) error {
+	s.c <- block
+	return nil
+}
+
+func (s *ChanSink) Close() error {
+	close(s.c)
+	return nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultSegmentHeights is the number of heights in a file of FileSink
+const DefaultSegmentHeights = 10000
+
+// FileSink writes the stream into files in a directory. The file of the heights in
+// [n*segment, (n+1)*segment) is named by n*segment, such as "pubmsgs-000000010000.log".
+// With SinkEncodingJSON, each msg is a line of "key#value", which is the format of msgqueue's file writer.
+// With SinkEncodingCodon, the files are the binary frames which can be read by pubmsg.Reader.
+type FileSink struct {
+	dir      string
+	segment  int64
+	encoding SinkEncoding
+	start    int64 // the first height of the current file
+	file     *os.File
+}
+
+var _ PubMsgSink = (*FileSink)(nil)
+
+func NewFileSink(dir string, segment int64, encoding SinkEncoding) (*FileSink, error) {
+	if err := encoding.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileSink{dir: dir, segment: segment, encoding: encoding, start: -1}, nil
+}
+
+// SegmentFileName returns the name of the file which holds height
+func SegmentFileName(height, segment int64) string {
+	return fmt.Sprintf("pubmsgs-%012d.log", height-height%segment)
+}
+
+func (s *FileSink) Name() string {
+	return SinkTypeFile + ":" + s.dir
+}
+
+func (s *FileSink) Encoding() SinkEncoding {
+	return s.encoding
+}
+
+func (s *FileSink) WriteBlock(block PubMsgBlock) error {
+	if start := block.Height - block.Height%s.segment; s.file == nil || start != s.start {
+		if err := s.rotate(start); err != nil {
+			return err
+		}
+	}
+	// a failed write is cut off, so that the retry does not follow a partial frame or line
+	size, err := s.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err = s.file.Write(block.encode(s.encoding)); err != nil {
+		if terr := s.file.Truncate(size); terr != nil {
+			return fmt.Errorf("%s, and truncate failed: %s", err.Error(), terr.Error())
+		}
+		_, _ = s.file.Seek(size, io.SeekStart)
+	}
+	return err
+}
+
+func encodePubMsgLines(msgs []PubMsg) []byte {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		buf.Write(msg.Key)
+		buf.WriteByte('#')
+		buf.Write(msg.Value)
+		buf.WriteString("\r\n")
+	}
+	return buf.Bytes()
+}
+
+func (s *FileSink) rotate(start int64) error {
+	if err := s.Close(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(s.dir, SegmentFileName(start, s.segment)),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.file, s.start = file, start
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
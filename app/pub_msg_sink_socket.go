@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// a client which is too slow to take a block in this time is disconnected
+const sinkClientWriteTimeout = 5 * time.Second
+
+// the number of blocks which can wait for a client, which is disconnected when its queue is full
+const sinkClientQueueSize = 100
+
+type sinkClient interface {
+	writeBlock(block PubMsgBlock) error
+	close()
+}
+
+// clientWriter writes the blocks in its queue to a client in its own goroutine, so that
+// a slow client neither delays the others nor blocks the sink
+type clientWriter struct {
+	client sinkClient
+	queue  chan PubMsgBlock
+}
+
+// broadcastSink sends the stream to all the connected clients. The clients only get
+// the blocks committed after they connect, and a block is not an error without clients.
+type broadcastSink struct {
+	name     string
+	encoding SinkEncoding
+	mtx      sync.Mutex
+	clients  map[*clientWriter]struct{}
+	writers  sync.WaitGroup
+	logger   log.Logger
+	closer   func() error
+}
+
+func newBroadcastSink(name string, encoding SinkEncoding, logger log.Logger) *broadcastSink {
+	return &broadcastSink{name: name, encoding: encoding, clients: make(map[*clientWriter]struct{}), logger: logger}
+}
+
+func (s *broadcastSink) Name() string {
+	return s.name
+}
+
+func (s *broadcastSink) Encoding() SinkEncoding {
+	return s.encoding
+}
+
+func (s *broadcastSink) addClient(c sinkClient) {
+	w := &clientWriter{client: c, queue: make(chan PubMsgBlock, sinkClientQueueSize)}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.clients[w] = struct{}{}
+	s.writers.Add(1)
+	go s.runClient(w)
+}
+
+func (s *broadcastSink) clientCount() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.clients)
+}
+
+func (s *broadcastSink) runClient(w *clientWriter) {
+	defer s.writers.Done()
+	defer w.client.close()
+	for block := range w.queue {
+		if err := w.client.writeBlock(block); err != nil {
+			s.removeClient(w, err.Error())
+			return
+		}
+	}
+}
+
+// removeClient stops queueing blocks to w, the queue is closed under the lock so that WriteBlock never sends to it
+func (s *broadcastSink) removeClient(w *clientWriter, reason string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.clients[w]; !ok {
+		return
+	}
+	s.logger.Info(fmt.Sprintf("client of sink %s is disconnected: %s", s.name, reason))
+	delete(s.clients, w)
+	close(w.queue)
+}
+
+func (s *broadcastSink) WriteBlock(block PubMsgBlock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for w := range s.clients {
+		select {
+		case w.queue <- block:
+		default:
+			s.logger.Info(fmt.Sprintf("client of sink %s is disconnected: too slow", s.name))
+			delete(s.clients, w)
+			close(w.queue)
+			// fails the write in progress, and the ones left in the queue
+			w.client.close()
+		}
+	}
+	return nil
+}
+
+// Close stops accepting clients, and waits until the connected ones are sent the queued blocks
+func (s *broadcastSink) Close() error {
+	err := s.closer()
+	s.mtx.Lock()
+	for w := range s.clients {
+		close(w.queue)
+	}
+	s.clients = make(map[*clientWriter]struct{})
+	s.mtx.Unlock()
+	s.writers.Wait()
+	return err
+}
+
+type unixSinkClient struct {
+	conn     net.Conn
+	encoding SinkEncoding
+}
+
+func (c unixSinkClient) writeBlock(block PubMsgBlock) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(sinkClientWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(block.encode(c.encoding))
+	return err
+}
+
+func (c unixSinkClient) close() {
+	_ = c.conn.Close()
+}
+
+// NewUnixSocketSink listens on sockPath, each client reads the stream in the format of FileSink
+func NewUnixSocketSink(sockPath string, encoding SinkEncoding, logger log.Logger) (PubMsgSink, error) {
+	if err := encoding.Validate(); err != nil {
+		return nil, err
+	}
+	// a socket file left by a former run prevents listening
+	if err := os.Remove(sockPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return nil, err
+	}
+	s := newBroadcastSink(SinkTypeUnix+":"+sockPath, encoding, logger)
+	s.closer = listener.Close
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.addClient(unixSinkClient{conn: conn, encoding: encoding})
+		}
+	}()
+	return s, nil
+}
+
+type websocketSinkClient struct {
+	conn     *websocket.Conn
+	encoding SinkEncoding
+}
+
+// each msg is sent as a text message of "key#value", or a binary message of its frame with SinkEncodingCodon
+func (c websocketSinkClient) writeBlock(block PubMsgBlock) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(sinkClientWriteTimeout)); err != nil {
+		return err
+	}
+	msgType := websocket.TextMessage
+	if c.encoding == SinkEncodingCodon {
+		msgType = websocket.BinaryMessage
+	}
+	for _, bz := range block.websocketMessages(c.encoding) {
+		if err := c.conn.WriteMessage(msgType, bz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c websocketSinkClient) close() {
+	_ = c.conn.Close()
+}
+
+// NewWebsocketSink serves the stream at addr, which looks like "127.0.0.1:8765/stream". The browsers
+// can connect from the pages of the same host or the ones in allowedOrigins, in which "*" allows any page.
+// The other clients, which do not send the Origin header, are always allowed.
+func NewWebsocketSink(addr string, encoding SinkEncoding, allowedOrigins []string, logger log.Logger) (PubMsgSink, error) {
+	if err := encoding.Validate(); err != nil {
+		return nil, err
+	}
+	path := "/"
+	if idx := strings.Index(addr, "/"); idx >= 0 {
+		addr, path = addr[:idx], addr[idx:]
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newBroadcastSink(SinkTypeWebsocket+":"+addr+path, encoding, logger)
+	upgrader := websocket.Upgrader{CheckOrigin: newOriginChecker(allowedOrigins)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.addClient(websocketSinkClient{conn: conn, encoding: encoding})
+		// the control frames from the client are handled while reading
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+	})
+	server := &http.Server{Handler: mux}
+	s.closer = func() error {
+		return server.Shutdown(context.Background())
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return s, nil
+}
+
+// newOriginChecker returns nil for an empty list, with which the websocket library only allows the same host
+func newOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(origin)] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowed["*"] {
+			return true
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return allowed[strings.ToLower(origin)]
+	}
+}
@@ -0,0 +1,496 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+	"github.com/coinexchain/dex/app/pubmsg"
+)
+
+func newPubMsgBlock(height int64, keys ...string) PubMsgBlock {
+	block := PubMsgBlock{Height: height}
+	for _, key := range keys {
+		block.Msgs = append(block.Msgs, PubMsg{Key: []byte(key), Value: []byte("{}")})
+	}
+	return block
+}
+
+// waitUntil polls cond until it holds. Unlike require.Eventually of this testify version,
+// cond is never called after it returns.
+func waitUntil(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// flakySink fails the first failures writes, and then waits for release before each write
+type flakySink struct {
+	mtx      sync.Mutex
+	failures int
+	release  chan struct{}
+	heights  []int64
+}
+
+func (s *flakySink) Name() string { return "flaky" }
+func (s *flakySink) Close() error { return nil }
+
+func (s *flakySink) WriteBlock(block PubMsgBlock) error {
+	s.mtx.Lock()
+	if s.failures > 0 {
+		s.failures--
+		s.mtx.Unlock()
+		return errors.New("failed")
+	}
+	s.mtx.Unlock()
+	if s.release != nil {
+		<-s.release
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.heights = append(s.heights, block.Height)
+	return nil
+}
+
+func (s *flakySink) getHeights() []int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]int64(nil), s.heights...)
+}
+
+func TestParseSinkConfig(t *testing.T) {
+	cfg, err := ParseSinkConfig("file:/tmp/msgs?segment=100")
+	require.Nil(t, err)
+	require.Equal(t, SinkTypeFile, cfg.Type)
+	require.Equal(t, "/tmp/msgs", cfg.Target)
+	require.Equal(t, SinkPolicyBlock, cfg.Policy)
+	require.Equal(t, DefaultSinkBufferSize, cfg.BufferSize)
+	require.Equal(t, "100", cfg.Params.Get("segment"))
+
+	cfg, err = ParseSinkConfig("ws:127.0.0.1:8765/stream?policy=buffer&buffer=10")
+	require.Nil(t, err)
+	require.Equal(t, SinkTypeWebsocket, cfg.Type)
+	require.Equal(t, "127.0.0.1:8765/stream", cfg.Target)
+	require.Equal(t, SinkPolicyBuffer, cfg.Policy)
+	require.Equal(t, 10, cfg.BufferSize)
+
+	cfg, err = ParseSinkConfig("ws:127.0.0.1:8765/stream?origins=https://a.com,https://b.com")
+	require.Nil(t, err)
+	require.Equal(t, "https://a.com,https://b.com", cfg.Params.Get("origins"))
+
+	cfg, err = ParseSinkConfig("unix:/tmp/msgs.sock")
+	require.Nil(t, err)
+	require.Equal(t, SinkPolicyDrop, cfg.Policy)
+	require.Equal(t, SinkEncodingJSON, cfg.Encoding)
+
+	cfg, err = ParseSinkConfig("unix:/tmp/msgs.sock?encoding=codon")
+	require.Nil(t, err)
+	require.Equal(t, SinkEncodingCodon, cfg.Encoding)
+
+	for _, item := range []string{"file", ":x", "file:", "unix:/a?policy=wait", "unix:/a?buffer=0", "unix:/a?encoding=xml"} {
+		_, err = ParseSinkConfig(item)
+		require.NotNil(t, err, item)
+	}
+	_, err = NewSink(SinkConfig{Type: "kafka", Target: "x"}, log.NewNopLogger())
+	require.NotNil(t, err)
+}
+
+func TestSinkPolicies(t *testing.T) {
+	sinks := NewPubMsgSinks(log.NewNopLogger())
+	require.NotNil(t, sinks.Add(&flakySink{}, SinkPolicy("wait"), 1))
+
+	// the block sink has written the blocks before Send returns
+	blockSink := &flakySink{failures: 2}
+	require.Nil(t, sinks.Add(blockSink, SinkPolicyBlock, 0))
+	sinks.Send(newPubMsgBlock(1, "k"))
+	sinks.Send(newPubMsgBlock(2, "k"))
+	require.Equal(t, []int64{1, 2}, blockSink.getHeights())
+	sinks.Close()
+
+	// the drop sink drops the block it fails to write, and the ones it has no room for
+	dropSink := &flakySink{failures: 1, release: make(chan struct{})}
+	require.Nil(t, sinks.Add(dropSink, SinkPolicyDrop, 1))
+	runner := sinks.runners[0]
+	sinks.Send(newPubMsgBlock(1, "k"))
+	waitUntil(t, func() bool { return sinks.Dropped()["flaky"] == 1 })
+	sinks.Send(newPubMsgBlock(2, "k"))
+	waitUntil(t, func() bool { return len(runner.queue) == 0 })
+	sinks.Send(newPubMsgBlock(3, "k"))
+	sinks.Send(newPubMsgBlock(4, "k"))
+	require.Equal(t, int64(2), sinks.Dropped()["flaky"])
+	close(dropSink.release)
+	sinks.Close()
+	require.Equal(t, []int64{2, 3}, dropSink.getHeights())
+
+	// the buffer sink retries until all the blocks are written
+	bufferSink := &flakySink{failures: 2}
+	require.Nil(t, sinks.Add(bufferSink, SinkPolicyBuffer, 1))
+	for h := int64(1); h <= 3; h++ {
+		sinks.Send(newPubMsgBlock(h, "k"))
+	}
+	require.Equal(t, int64(0), sinks.Dropped()["flaky"])
+	sinks.Close()
+	require.Equal(t, []int64{1, 2, 3}, bufferSink.getHeights())
+}
+
+func TestCloseStuckSink(t *testing.T) {
+	defer func(timeout time.Duration) { sinkCloseTimeout = timeout }(sinkCloseTimeout)
+	sinkCloseTimeout = 50 * time.Millisecond
+
+	// nobody reads the channel, so the runner is stuck in writing block 1
+	sinks := NewPubMsgSinks(log.NewNopLogger())
+	sink, c := NewChanSink("stuck", 0)
+	require.Nil(t, sinks.Add(sink, SinkPolicyBuffer, 1))
+	sinks.Send(newPubMsgBlock(1, "k"))
+	sinks.Send(newPubMsgBlock(2, "k"))
+	sinks.Close()
+	// the sink is left open
+	block := <-c
+	require.Equal(t, int64(1), block.Height)
+}
+
+func TestCloseAppWithStuckSink(t *testing.T) {
+	defer func(timeout time.Duration) { sinkCloseTimeout = timeout }(sinkCloseTimeout)
+	sinkCloseTimeout = 50 * time.Millisecond
+
+	app := initAppWithBaseAccounts()
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	sink, c := NewChanSink("stuck", 0)
+	require.Nil(t, app.AddPubMsgSink(sink, SinkPolicyBlock, 0))
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	closed := make(chan struct{})
+	go func() {
+		app.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close is stuck by the sink")
+	}
+	block := <-c
+	require.Equal(t, int64(1), block.Height)
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sink")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileSink(dir, 10, SinkEncodingJSON)
+	require.Nil(t, err)
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(9, "a", "commit")))
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(10, "b", "commit")))
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(11, "c", "commit")))
+	require.Nil(t, sink.Close())
+
+	bz, err := ioutil.ReadFile(filepath.Join(dir, "pubmsgs-000000000000.log"))
+	require.Nil(t, err)
+	require.Equal(t, "a#{}\r\ncommit#{}\r\n", string(bz))
+	bz, err = ioutil.ReadFile(filepath.Join(dir, SegmentFileName(11, 10)))
+	require.Nil(t, err)
+	require.Equal(t, "b#{}\r\ncommit#{}\r\nc#{}\r\ncommit#{}\r\n", string(bz))
+}
+
+func TestCodonFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sink")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	slash := NotificationSlash{Version: SlashVersion, Validator: "val", Power: "10", Reason: "double_sign", Jailed: true}
+	result := NotificationProposalResult{Version: ProposalResultVersion, ProposalID: 1, Result: ProposalResultDropped}
+	block := PubMsgBlock{Height: 1, Msgs: []PubMsg{
+		newPubMsg(KeySlash, slash),
+		newPubMsg(KeyProposalResult, result),
+		// a notification replayed from the outbox, which is not encoded by codon
+		{Key: []byte(KeySlash), Value: []byte("not json")},
+		{Key: []byte("create_order_info"), Value: []byte(`{"id":"x"}`)},
+		{Key: []byte("commit"), Value: []byte("{}")},
+	}}
+	encodePubMsgsInPlace(block.Msgs, true)
+	sink, err := NewFileSink(dir, 10, SinkEncodingCodon)
+	require.Nil(t, err)
+	require.Nil(t, sink.WriteBlock(block))
+	require.Nil(t, sink.Close())
+
+	file, err := os.Open(filepath.Join(dir, SegmentFileName(1, 10)))
+	require.Nil(t, err)
+	defer file.Close()
+	r := pubmsg.NewReader(file)
+	var payloads []interface{}
+	for {
+		msg, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		require.Equal(t, string(block.Msgs[len(payloads)].Key), msg.Key)
+		payload, err := msg.Payload()
+		require.Nil(t, err)
+		payloads = append(payloads, payload)
+	}
+	require.Equal(t, []interface{}{
+		slash,
+		result,
+		json.RawMessage("not json"),
+		json.RawMessage(`{"id":"x"}`),
+		json.RawMessage("{}"),
+	}, payloads)
+}
+
+func TestNotifyTxToBinary(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	tx := NotificationTx{
+		Version:   NotifyTxVersion,
+		Signers:   []sdk.AccAddress{addr},
+		Transfers: []TransferRecord{{Sender: addr.String(), Recipient: addr.String(), Amount: "1cet"}},
+		MsgTypes:  []string{"MsgSend"},
+		Height:    10,
+		Hash:      []byte{1, 2, 3},
+		Fee: TxFeeInfo{
+			Payer: addr.String(), Amount: []FeeRecord{{Denom: "cet", Amount: "100"}}, Charged: true,
+			GasLimit: 1000, GasUsed: 800, EffectiveGasPrice: []GasPriceRecord{{Denom: "cet", Price: "0.125000000000000000"}},
+			ToFeeCollector: []FeeRecord{{Denom: "cet", Amount: "100"}}, ToCommunityPool: []FeeRecord{{Denom: "cet", Amount: "1"}},
+		},
+	}
+	cdc := MakeCodec()
+	stdTx := auth.NewStdTx(nil, auth.NewStdFee(1000, dex.NewCetCoins(100)), nil, "memo")
+	txBytes := cdc.MustMarshalBinaryLengthPrefixed(stdTx)
+	msg := PubMsg{Key: []byte(KeyNotifyTx), payload: func() interface{} {
+		return notifyTxPayload{n: tx, stdTx: stdTx, tx: txBytes}
+	}}.encodedWith(true)
+
+	// the JSON carries the tx in JSON
+	var n map[string]interface{}
+	require.Nil(t, json.Unmarshal(msg.Value, &n))
+	require.Contains(t, n["tx_json"], `"memo":"memo"`)
+
+	// while the binary carries the bytes of the tx in the block
+	frame := toBinaryPubMsg(msg)
+	require.Equal(t, pubmsg.FormatCodon, frame.Format)
+	payload, err := frame.Payload()
+	require.Nil(t, err)
+	tx.Tx = txBytes
+	require.Equal(t, tx, payload)
+	decoded, err := auth.DefaultTxDecoder(cdc)(payload.(NotificationTx).Tx)
+	require.Nil(t, err)
+	require.Equal(t, "memo", decoded.(auth.StdTx).Memo)
+}
+
+func TestUnixSocketSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unix_sink")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	sockPath := filepath.Join(dir, "msgs.sock")
+
+	sink, err := NewUnixSocketSink(sockPath, SinkEncodingJSON, log.NewNopLogger())
+	require.Nil(t, err)
+	// no client is not an error
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(1, "a")))
+
+	conn, err := net.Dial("unix", sockPath)
+	require.Nil(t, err)
+	defer conn.Close()
+	waitUntil(t, func() bool { return sink.(*broadcastSink).clientCount() == 1 })
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(2, "b", "commit")))
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	require.Nil(t, err)
+	require.Equal(t, "b#{}\r\n", line)
+	line, err = r.ReadString('\n')
+	require.Nil(t, err)
+	require.Equal(t, "commit#{}\r\n", line)
+	require.Nil(t, sink.Close())
+}
+
+func TestWebsocketSink(t *testing.T) {
+	sink, err := NewWebsocketSink("127.0.0.1:0/stream", SinkEncodingJSON, nil, log.NewNopLogger())
+	require.NotNil(t, sink)
+	require.Nil(t, err)
+	require.Nil(t, sink.Close())
+
+	sink, err = NewWebsocketSink("127.0.0.1:18765/stream", SinkEncodingJSON, nil, log.NewNopLogger())
+	require.Nil(t, err)
+	defer sink.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws://127.0.0.1:18765/stream", nil)
+	require.Nil(t, err)
+	defer conn.Close()
+	waitUntil(t, func() bool { return sink.(*broadcastSink).clientCount() == 1 })
+
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(1, "a", "commit")))
+	_, bz, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, "a#{}", string(bz))
+	_, bz, err = conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, "commit#{}", string(bz))
+}
+
+func TestWebsocketSinkOrigins(t *testing.T) {
+	sink, err := NewWebsocketSink("127.0.0.1:18767/stream", SinkEncodingJSON, []string{"https://example.com"}, log.NewNopLogger())
+	require.Nil(t, err)
+	defer sink.Close()
+
+	dial := func(origin string) error {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial("ws://127.0.0.1:18767/stream", header)
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+	require.Nil(t, dial(""))
+	require.Nil(t, dial("https://example.com"))
+	require.Nil(t, dial("http://127.0.0.1:18767"))
+	require.NotNil(t, dial("https://evil.com"))
+
+	require.Nil(t, newOriginChecker(nil))
+	r := &http.Request{Host: "127.0.0.1:18767", Header: http.Header{"Origin": []string{"https://evil.com"}}}
+	require.True(t, newOriginChecker([]string{"*"})(r))
+}
+
+// blockingClient never finishes a write until it is closed
+type blockingClient struct {
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *blockingClient) writeBlock(PubMsgBlock) error {
+	<-c.closed
+	return errors.New("closed")
+}
+
+func (c *blockingClient) close() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+type recordingClient struct {
+	mtx     sync.Mutex
+	heights []int64
+	closed  bool
+}
+
+func (c *recordingClient) writeBlock(block PubMsgBlock) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.heights = append(c.heights, block.Height)
+	return nil
+}
+
+func (c *recordingClient) count() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.heights)
+}
+
+func (c *recordingClient) close() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.closed = true
+}
+
+func TestBroadcastSinkSlowClient(t *testing.T) {
+	sink := newBroadcastSink("test", SinkEncodingJSON, log.NewNopLogger())
+	sink.closer = func() error { return nil }
+	slow := &blockingClient{closed: make(chan struct{})}
+	fast := &recordingClient{}
+	sink.addClient(slow)
+	sink.addClient(fast)
+
+	// the slow client is disconnected when its queue is full, without delaying the fast one
+	n := int64(sinkClientQueueSize + 2)
+	for h := int64(1); h <= n; h++ {
+		require.Nil(t, sink.WriteBlock(newPubMsgBlock(h, "k")))
+		waitUntil(t, func() bool { return fast.count() == int(h) })
+	}
+	require.Equal(t, 1, sink.clientCount())
+	<-slow.closed
+
+	// the queued blocks are sent before Close returns
+	require.Nil(t, sink.WriteBlock(newPubMsgBlock(n+1, "k")))
+	n++
+	require.Nil(t, sink.Close())
+	require.Equal(t, 0, sink.clientCount())
+	require.True(t, fast.closed)
+	require.Equal(t, int(n), len(fast.heights))
+	require.Equal(t, n, fast.heights[n-1])
+}
+
+func TestCodonWebsocketSink(t *testing.T) {
+	sink, err := NewWebsocketSink("127.0.0.1:18766/stream", SinkEncodingCodon, nil, log.NewNopLogger())
+	require.Nil(t, err)
+	defer sink.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws://127.0.0.1:18766/stream", nil)
+	require.Nil(t, err)
+	defer conn.Close()
+	waitUntil(t, func() bool { return sink.(*broadcastSink).clientCount() == 1 })
+
+	unlock := NotificationUnlock{Version: UnlockVersion, Address: "addr", Denom: "cet", Amount: "1", Spendable: "2"}
+	msgs := []PubMsg{newPubMsg(KeyUnlock, unlock)}
+	encodePubMsgsInPlace(msgs, true)
+	require.Nil(t, sink.WriteBlock(PubMsgBlock{Height: 1, Msgs: msgs}))
+	msgType, bz, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, websocket.BinaryMessage, msgType)
+	msg, n, err := pubmsg.DecodeFrame(bz)
+	require.Nil(t, err)
+	require.Equal(t, len(bz), n)
+	require.Equal(t, KeyUnlock, msg.Key)
+	payload, err := msg.Payload()
+	require.Nil(t, err)
+	require.Equal(t, unlock, payload)
+}
+
+func TestChanSinkOfApp(t *testing.T) {
+	app := initAppWithBaseAccounts()
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	sink, c := NewChanSink("test", 1)
+	require.Nil(t, app.AddPubMsgSink(sink, SinkPolicyBuffer, 10))
+
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	block := <-c
+	require.Equal(t, int64(1), block.Height)
+	last := block.Msgs[len(block.Msgs)-1]
+	require.Equal(t, "commit", string(last.Key))
+	require.Equal(t, "height_info", string(block.Msgs[0].Key))
+
+	app.pubMsgSinks.Close()
+	_, ok := <-c
+	require.False(t, ok)
+}
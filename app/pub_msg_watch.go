@@ -0,0 +1,188 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// FlagPubMsgWatchList (or the key in app.toml) is the addresses whose txs are published. If it is empty,
+// the PubMsgs of all the txs are published. The PubMsgs of blocks, such as height_info and commit,
+// are always published.
+const FlagPubMsgWatchList = "pubmsg-watch-list"
+
+// RoutePubMsgWatchList is the admin route to get or update PubMsgWatchList
+const RoutePubMsgWatchList = "/pubmsg-watch-list"
+
+// PubMsgWatchList filters the PubMsgs of txs by the addresses of their signers, recipients and transfers
+type PubMsgWatchList struct {
+	Addresses []string `json:"addresses"`
+}
+
+// PubMsgWatchListStatus is returned by RoutePubMsgWatchList. An update is pending until the next commit.
+type PubMsgWatchListStatus struct {
+	Current PubMsgWatchList  `json:"current"`
+	Pending *PubMsgWatchList `json:"pending,omitempty"`
+}
+
+func PubMsgWatchListFromViper() PubMsgWatchList {
+	return PubMsgWatchList{Addresses: viper.GetStringSlice(FlagPubMsgWatchList)}
+}
+
+func (wl PubMsgWatchList) Validate() error {
+	_, err := wl.normalized()
+	return err
+}
+
+// normalized returns wl with the addresses in the form of AccAddress.String(), which is compared with
+// the addresses of txs, since bech32 also accepts the upper case ones
+func (wl PubMsgWatchList) normalized() (PubMsgWatchList, error) {
+	if wl.Addresses == nil {
+		return wl, nil
+	}
+	res := PubMsgWatchList{Addresses: make([]string, len(wl.Addresses))}
+	for i, s := range wl.Addresses {
+		addr, err := sdk.AccAddressFromBech32(s)
+		if err != nil {
+			return wl, fmt.Errorf("invalid address %s in %s: %s", s, FlagPubMsgWatchList, err.Error())
+		}
+		res.Addresses[i] = addr.String()
+	}
+	return res, nil
+}
+
+func (app *CetChainApp) initPubMsgWatchList() {
+	wl, err := PubMsgWatchListFromViper().normalized()
+	if err != nil {
+		cmn.Exit(err.Error())
+	}
+	app.applyPubMsgWatchList(wl)
+}
+
+// applyPubMsgWatchList must be called when DeliverTx is not running, i.e. during initialization or in Commit.
+// The addresses of wl must be normalized.
+func (app *CetChainApp) applyPubMsgWatchList(wl PubMsgWatchList) {
+	watched := make(map[string]bool, len(wl.Addresses))
+	for _, addr := range wl.Addresses {
+		watched[addr] = true
+	}
+	app.pubMsgWatched = watched
+
+	app.pubMsgWatchMtx.Lock()
+	defer app.pubMsgWatchMtx.Unlock()
+	app.pubMsgWatchList = wl
+}
+
+// UpdatePubMsgWatchList validates wl, which takes effect after the next commit
+func (app *CetChainApp) UpdatePubMsgWatchList(wl PubMsgWatchList) error {
+	wl, err := wl.normalized()
+	if err != nil {
+		return err
+	}
+	app.pubMsgWatchMtx.Lock()
+	defer app.pubMsgWatchMtx.Unlock()
+	app.pendingPubMsgWatchList = &wl
+	return nil
+}
+
+func (app *CetChainApp) GetPubMsgWatchListStatus() PubMsgWatchListStatus {
+	app.pubMsgWatchMtx.Lock()
+	defer app.pubMsgWatchMtx.Unlock()
+	return PubMsgWatchListStatus{
+		Current: app.pubMsgWatchList,
+		Pending: app.pendingPubMsgWatchList,
+	}
+}
+
+func (app *CetChainApp) applyPendingPubMsgWatchList() {
+	app.pubMsgWatchMtx.Lock()
+	pending := app.pendingPubMsgWatchList
+	app.pendingPubMsgWatchList = nil
+	app.pubMsgWatchMtx.Unlock()
+
+	if pending != nil {
+		app.applyPubMsgWatchList(*pending)
+		app.Logger().Info(fmt.Sprintf("PubMsg watch list is updated with %d addresses", len(pending.Addresses)))
+	}
+}
+
+// isWatchedTx tells if the PubMsgs of a tx are published. The transfers are only found in the events
+// of a successful tx, so the recipients of the failed sends are taken from the msgs.
+func (app *CetChainApp) isWatchedTx(stdTx auth.StdTx, events []abci.Event) bool {
+	if len(app.pubMsgWatched) == 0 {
+		return true
+	}
+	for _, signer := range stdTx.GetSigners() {
+		if app.pubMsgWatched[signer.String()] {
+			return true
+		}
+	}
+	for _, recipient := range getMsgRecipients(stdTx.Msgs) {
+		if app.pubMsgWatched[recipient.String()] {
+			return true
+		}
+	}
+	for _, msgEvents := range DecodeTxEvents(events) {
+		for _, moduleEvents := range msgEvents.Modules {
+			for _, rec := range moduleEvents.Transfers() {
+				if app.pubMsgWatched[rec.Recipient] {
+					return true
+				}
+				// the inputs of InputOutputCoins are joined by commas
+				for _, sender := range strings.Split(rec.Sender, ",") {
+					if app.pubMsgWatched[sender] {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+func getMsgRecipients(msgs []sdk.Msg) []sdk.AccAddress {
+	var res []sdk.AccAddress
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case bankx.MsgSend:
+			res = append(res, msg.ToAddress)
+		case bankx.MsgSupervisedSend:
+			res = append(res, msg.ToAddress)
+		case bankx.MsgMultiSend:
+			for _, out := range msg.Outputs {
+				res = append(res, out.Address)
+			}
+		}
+	}
+	return res
+}
+
+func (app *CetChainApp) registerPubMsgWatchListRoutes(r *mux.Router) {
+	r.HandleFunc(RoutePubMsgWatchList, func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, app.GetPubMsgWatchListStatus())
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc(RoutePubMsgWatchList, func(w http.ResponseWriter, r *http.Request) {
+		var wl PubMsgWatchList
+		if err := admin.ReadJSON(r, &wl); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := app.UpdatePubMsgWatchList(wl); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		admin.WriteJSON(w, app.GetPubMsgWatchListStatus())
+	}).Methods(http.MethodPost)
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func TestPubMsgWatchList(t *testing.T) {
+	key, _, fromAddr := testutil.KeyPubAddr()
+	_, _, toAddr := testutil.KeyPubAddr()
+	_, _, otherAddr := testutil.KeyPubAddr()
+	acc0 := auth.BaseAccount{Address: fromAddr, Coins: dex.NewCetCoins(30000000000)}
+	acc1 := auth.BaseAccount{Address: toAddr, Coins: dex.NewCetCoins(100)}
+	app := initAppWithBaseAccounts(acc0, acc1)
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+
+	require.NotNil(t, app.UpdatePubMsgWatchList(PubMsgWatchList{Addresses: []string{"invalid"}}))
+	require.Nil(t, app.UpdatePubMsgWatchList(PubMsgWatchList{Addresses: []string{otherAddr.String()}}))
+	status := app.GetPubMsgWatchListStatus()
+	require.Empty(t, status.Current.Addresses)
+	require.Equal(t, []string{otherAddr.String()}, status.Pending.Addresses)
+
+	seq := uint64(0)
+	deliverSend := func(height int64) {
+		app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: height, ChainID: testChainID}})
+		tx := newStdTxBuilder().
+			Msgs(bankx.NewMsgSend(fromAddr, toAddr, dex.NewCetCoins(100), 0)).GasAndFee(1000000, 100).
+			AccNumSeqKey(0, seq, key).Build()
+		require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+		seq++
+		app.EndBlock(abci.RequestEndBlock{Height: height})
+	}
+	countTxMsgs := func() int {
+		count := 0
+		for _, msg := range app.pubMsgs {
+			if string(msg.Key) == KeyNotifyTx {
+				count++
+			}
+		}
+		return count
+	}
+
+	// the update takes effect after the commit
+	deliverSend(1)
+	require.Equal(t, 1, countTxMsgs())
+	app.Commit()
+	require.Nil(t, app.GetPubMsgWatchListStatus().Pending)
+
+	// neither the signer nor the recipient is watched
+	deliverSend(2)
+	require.Equal(t, 0, countTxMsgs())
+	require.Equal(t, KeyHeightInfo, string(app.pubMsgs[0].Key))
+	// the upper case address is the same as the lower case one
+	require.Nil(t, app.UpdatePubMsgWatchList(PubMsgWatchList{Addresses: []string{otherAddr.String(), strings.ToUpper(toAddr.String())}}))
+	require.Equal(t, toAddr.String(), app.GetPubMsgWatchListStatus().Pending.Addresses[1])
+	app.Commit()
+
+	// the recipient is watched
+	deliverSend(3)
+	require.Equal(t, 1, countTxMsgs())
+	app.Commit()
+
+	// a failed tx to a watched recipient
+	require.True(t, app.isWatchedTx(auth.StdTx{Msgs: []sdk.Msg{bankx.NewMsgSend(otherAddr, toAddr, nil, 0)}}, nil))
+	require.False(t, app.isWatchedTx(auth.StdTx{Msgs: []sdk.Msg{bankx.NewMsgSend(fromAddr, fromAddr, nil, 0)}}, nil))
+	// a transfer from the inputs of a multi-send
+	require.True(t, app.isWatchedTx(auth.StdTx{}, []abci.Event{
+		newEvent("message", "sender", "in1"),
+		newEvent("message", "sender", otherAddr.String()),
+		newEvent("transfer", "recipient", "out1", "amount", "1cet"),
+		newEvent("message", "action", "multi_send"),
+	}))
+}
@@ -4,11 +4,54 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/coinexchain/cet-sdk/msgqueue"
+	dex "github.com/coinexchain/cet-sdk/types"
 )
 
 type PubMsg struct {
 	Key   []byte
 	Value []byte
+	// payload returns the notification which is marshaled to Value off the consensus critical path,
+	// it is nil once Value is ready
+	payload func() interface{}
+	// the codon encoded notification, which is set only if a sink wants SinkEncodingCodon.
+	// It is nil for the other msgs, which are written as JSON by such sinks.
+	binary []byte
+}
+
+// newPubMsg returns a msg whose payload is marshaled to JSON by the PubMsg queue, so payload must
+// not be changed after it is pushed
+func newPubMsg(key string, payload interface{}) PubMsg {
+	return PubMsg{Key: []byte(key), payload: func() interface{} { return payload }}
+}
+
+// dualPayload is implemented by the payloads which are different in the binary stream,
+// such as the one of notify_tx, which carries the tx in binary instead of JSON
+type dualPayload interface {
+	jsonPayload() interface{}
+	codonPayload() interface{}
+}
+
+// encoded returns msg with its Value ready
+func (msg PubMsg) encoded() PubMsg {
+	return msg.encodedWith(false)
+}
+
+// encodedWith returns msg with its Value ready, and also its binary if withCodon is true.
+// The payload is built only once for both of them.
+func (msg PubMsg) encodedWith(withCodon bool) PubMsg {
+	if msg.payload == nil {
+		return msg
+	}
+	jsonPayload := msg.payload()
+	codonPayload := jsonPayload
+	if p, ok := jsonPayload.(dualPayload); ok {
+		jsonPayload, codonPayload = p.jsonPayload(), p.codonPayload()
+	}
+	msg.Value, msg.payload = dex.SafeJSONMarshal(jsonPayload), nil
+	if withCodon {
+		msg.binary = encodeCodonPayload(string(msg.Key), codonPayload)
+	}
+	return msg
 }
 
 func collectKafkaEvents(events []abci.Event, app *CetChainApp) []abci.Event {
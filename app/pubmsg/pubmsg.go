@@ -0,0 +1,180 @@
+// Package pubmsg encodes and decodes the binary PubMsg stream, which is written by the sinks with
+// "encoding=codon". Each msg is a frame of:
+//
+//	uvarint(len(key)) | key | format | uvarint(len(value)) | value
+//
+// The values of the notifications defined in app/notification are encoded by the codec package,
+// while the others, such as the msgs produced by the modules and "commit", are kept as JSON.
+// A file or a unix socket is a sequence of frames, which can be read by Reader, and each binary
+// message of a websocket is a frame, which can be decoded by DecodeFrame.
+package pubmsg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/coinexchain/dex/codec"
+)
+
+// The formats of the values in the frames
+const (
+	FormatJSON  byte = 0
+	FormatCodon byte = 1
+)
+
+// MaxFrameFieldSize limits the length of the key or the value of a frame, so that a corrupted
+// stream is reported instead of exhausting the memory
+const MaxFrameFieldSize = 64 << 20
+
+var (
+	ErrUnknownFormat = errors.New("unknown format of the value")
+	ErrFieldTooLarge = errors.New("field of the frame is too large")
+)
+
+// Msg is a PubMsg in the binary stream
+type Msg struct {
+	Key    string
+	Format byte
+	Value  []byte
+}
+
+// AppendFrame appends the frame of msg to buf
+func AppendFrame(buf []byte, msg Msg) []byte {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(msg.Key)))
+	buf = append(buf, lenBuf[:n]...)
+	buf = append(buf, msg.Key...)
+	buf = append(buf, msg.Format)
+	n = binary.PutUvarint(lenBuf[:], uint64(len(msg.Value)))
+	buf = append(buf, lenBuf[:n]...)
+	return append(buf, msg.Value...)
+}
+
+// DecodeFrame decodes the frame at the beginning of bz, and returns the number of bytes it takes
+func DecodeFrame(bz []byte) (Msg, int, error) {
+	var msg Msg
+	key, total, err := getField(bz)
+	if err != nil {
+		return msg, 0, err
+	}
+	if total >= len(bz) {
+		return msg, 0, io.ErrUnexpectedEOF
+	}
+	msg.Key = string(key)
+	msg.Format = bz[total]
+	total++
+	value, n, err := getField(bz[total:])
+	if err != nil {
+		return msg, 0, err
+	}
+	msg.Value = make([]byte, len(value))
+	copy(msg.Value, value)
+	return msg, total + n, nil
+}
+
+func getField(bz []byte) ([]byte, int, error) {
+	length, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if length > MaxFrameFieldSize {
+		return nil, 0, ErrFieldTooLarge
+	}
+	if uint64(len(bz)-n) < length {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	return bz[n : n+int(length)], n + int(length), nil
+}
+
+// Reader reads the frames from a stream, such as a file or a unix socket of the sinks
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next msg. io.EOF is returned if the stream ends between two frames,
+// and io.ErrUnexpectedEOF if it ends inside a frame.
+func (r *Reader) Next() (Msg, error) {
+	var msg Msg
+	key, err := r.readField()
+	if err != nil {
+		return msg, err
+	}
+	msg.Key = string(key)
+	if msg.Format, err = r.r.ReadByte(); err != nil {
+		return msg, unexpectedEOF(err)
+	}
+	if msg.Value, err = r.readField(); err != nil {
+		return msg, unexpectedEOF(err)
+	}
+	return msg, nil
+}
+
+func (r *Reader) readField() ([]byte, error) {
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if length > MaxFrameFieldSize {
+		return nil, ErrFieldTooLarge
+	}
+	bz := make([]byte, length)
+	if _, err = io.ReadFull(r.r, bz); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return bz, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// EncodePayload encodes a notification struct of app/notification with the codec package
+func EncodePayload(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := codec.EncodeAny(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Payload decodes the value of msg. It returns a notification struct of app/notification
+// for FormatCodon, and json.RawMessage for FormatJSON.
+func (msg Msg) Payload() (interface{}, error) {
+	switch msg.Format {
+	case FormatJSON:
+		return json.RawMessage(msg.Value), nil
+	case FormatCodon:
+		v, err := decodeAny(msg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s failed: %s", msg.Key, err.Error())
+		}
+		return v, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// decodeAny returns an error instead of panicking, as codec.DecodeAny does for the unknown types
+func decodeAny(bz []byte) (v interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if len(bz) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	v, _, err = codec.DecodeAny(bz)
+	return
+}
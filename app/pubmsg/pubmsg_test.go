@@ -0,0 +1,82 @@
+package pubmsg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinexchain/dex/app/notification"
+)
+
+func TestFrames(t *testing.T) {
+	msgs := []Msg{
+		{Key: "commit", Format: FormatJSON, Value: []byte("{}")},
+		{Key: "empty", Format: FormatJSON, Value: []byte{}},
+		{Key: "big", Format: FormatCodon, Value: bytes.Repeat([]byte{7}, 300)},
+	}
+	var buf []byte
+	boundaries := map[int]bool{0: true}
+	for _, msg := range msgs {
+		buf = AppendFrame(buf, msg)
+		boundaries[len(buf)] = true
+	}
+
+	bz := buf
+	for _, msg := range msgs {
+		decoded, n, err := DecodeFrame(bz)
+		require.Nil(t, err)
+		require.Equal(t, msg, decoded)
+		bz = bz[n:]
+	}
+	require.Empty(t, bz)
+
+	// a stream cut inside a frame is reported
+	for i := 0; i <= len(buf); i++ {
+		r := NewReader(bytes.NewReader(buf[:i]))
+		var err error
+		for err == nil {
+			_, err = r.Next()
+		}
+		if boundaries[i] {
+			require.Equal(t, io.EOF, err, i)
+		} else {
+			require.Equal(t, io.ErrUnexpectedEOF, err, i)
+		}
+	}
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], MaxFrameFieldSize+1)
+	_, _, err := DecodeFrame(lenBuf[:n])
+	require.Equal(t, ErrFieldTooLarge, err)
+	_, err = NewReader(bytes.NewReader(lenBuf[:n])).Next()
+	require.Equal(t, ErrFieldTooLarge, err)
+}
+
+func TestPayload(t *testing.T) {
+	for _, v := range []interface{}{
+		notification.NotificationProposalResult{Version: 1, ProposalID: 2, Result: "dropped"},
+		notification.NotificationProposalResult{Version: 1, ProposalID: 3, Result: "passed", HasTallyResult: true,
+			TallyResult: notification.TallyResultRecord{Yes: "10", Abstain: "0", No: "0", NoWithVeto: "0"}},
+		notification.NotificationValidatorSetUpdate{Version: 1, Updates: []notification.ValidatorUpdateRecord{
+			{ConsensusPubKey: "key", Operator: "op", Power: 0, DropReason: "jailed"},
+		}},
+	} {
+		bz, err := EncodePayload(v)
+		require.Nil(t, err)
+		payload, err := Msg{Key: "k", Format: FormatCodon, Value: bz}.Payload()
+		require.Nil(t, err)
+		require.Equal(t, v, payload)
+	}
+
+	payload, err := Msg{Key: "commit", Format: FormatJSON, Value: []byte("{}")}.Payload()
+	require.Nil(t, err)
+	require.Equal(t, json.RawMessage("{}"), payload)
+	_, err = Msg{Key: "k", Format: FormatCodon, Value: []byte{1, 2, 3, 4, 5}}.Payload()
+	require.NotNil(t, err)
+	_, err = Msg{Key: "k", Format: 9, Value: []byte("{}")}.Payload()
+	require.Equal(t, ErrUnknownFormat, err)
+}
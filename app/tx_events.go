@@ -0,0 +1,146 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	stypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// MsgEvents is the events emitted in handling a msg of a tx. BaseApp ends the events of
+// each msg with a message event holding the msg type as "action".
+type MsgEvents struct {
+	Index   int
+	Action  string
+	Modules []ModuleEvents
+}
+
+// ModuleEvents is the events emitted by a module in handling a msg. A handler ends its events with
+// a message event holding its name as "module", and the events emitted by the keepers of the other
+// modules it calls, such as the transfers of bank, come before that. The events after the last such
+// message event of a msg are put into a ModuleEvents without Module.
+type ModuleEvents struct {
+	Module string
+	Sender string
+	Events []abci.Event
+}
+
+// DecodeTxEvents groups the events of a DeliverTx by msg and then by module
+func DecodeTxEvents(events []abci.Event) []MsgEvents {
+	var res []MsgEvents
+	var modules []ModuleEvents
+	start := 0
+	for i, event := range events {
+		if event.Type != sdk.EventTypeMessage {
+			continue
+		}
+		if action, ok := getAttr(event, sdk.AttributeKeyAction); ok {
+			if start < i {
+				modules = append(modules, ModuleEvents{Events: events[start:i]})
+			}
+			res = append(res, MsgEvents{Index: len(res), Action: action, Modules: modules})
+			modules, start = nil, i+1
+		} else if module, ok := getAttr(event, sdk.AttributeKeyModule); ok {
+			sender, _ := getAttr(event, sdk.AttributeKeySender)
+			modules = append(modules, ModuleEvents{Module: module, Sender: sender, Events: events[start : i+1]})
+			start = i + 1
+		}
+	}
+	return res
+}
+
+func getAttr(event abci.Event, key string) (string, bool) {
+	for _, attr := range event.Attributes {
+		if string(attr.Key) == key {
+			return string(attr.Value), true
+		}
+	}
+	return "", false
+}
+
+// isSenderEvent tells if event is the message event which bank emits for a sender
+func isSenderEvent(event abci.Event) bool {
+	if event.Type != sdk.EventTypeMessage || len(event.Attributes) != 1 {
+		return false
+	}
+	return string(event.Attributes[0].Key) == bank.AttributeKeySender
+}
+
+// Transfers returns the transfers made by bank. SendCoins emits a transfer event followed by the message
+// event of its sender, while InputOutputCoins emits the message events of all the inputs before the
+// transfer events of the outputs, whose senders are the inputs, joined by commas if there are several.
+func (m ModuleEvents) Transfers() []TransferRecord {
+	var res []TransferRecord
+	var inputs []string
+	for i := 0; i < len(m.Events); i++ {
+		event := m.Events[i]
+		if isSenderEvent(event) {
+			// a run of the message events starts the inputs of another InputOutputCoins
+			if i == 0 || !isSenderEvent(m.Events[i-1]) {
+				inputs = nil
+			}
+			inputs = append(inputs, string(event.Attributes[0].Value))
+			continue
+		}
+		if event.Type != bank.EventTypeTransfer {
+			continue
+		}
+		var rec TransferRecord
+		rec.Recipient, _ = getAttr(event, bank.AttributeKeyRecipient)
+		rec.Amount, _ = getAttr(event, sdk.AttributeKeyAmount)
+		if i+1 < len(m.Events) && isSenderEvent(m.Events[i+1]) {
+			rec.Sender = string(m.Events[i+1].Attributes[0].Value)
+			i++
+		} else {
+			rec.Sender = strings.Join(inputs, ",")
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// BeginUnbondings returns the unbondings started by staking, whose delegator is the sender of the msg
+func (m ModuleEvents) BeginUnbondings() []NotificationBeginUnbonding {
+	var res []NotificationBeginUnbonding
+	for _, event := range m.Events {
+		if event.Type != stypes.EventTypeUnbond {
+			continue
+		}
+		n := NotificationBeginUnbonding{Version: BeginUnbondingVersion, Delegator: m.Sender}
+		n.Validator, _ = getAttr(event, stypes.AttributeKeyValidator)
+		n.Amount, _ = getAttr(event, sdk.AttributeKeyAmount)
+		n.CompletionTime = getCompletionTime(event)
+		res = append(res, n)
+	}
+	return res
+}
+
+// BeginRedelegations returns the redelegations started by staking, whose delegator is the sender of the msg
+func (m ModuleEvents) BeginRedelegations() []NotificationBeginRedelegation {
+	var res []NotificationBeginRedelegation
+	for _, event := range m.Events {
+		if event.Type != stypes.EventTypeRedelegate {
+			continue
+		}
+		n := NotificationBeginRedelegation{Version: BeginRedelegationVersion, Delegator: m.Sender}
+		n.ValidatorSrc, _ = getAttr(event, stypes.AttributeKeySrcValidator)
+		n.ValidatorDst, _ = getAttr(event, stypes.AttributeKeyDstValidator)
+		n.Amount, _ = getAttr(event, sdk.AttributeKeyAmount)
+		n.CompletionTime = getCompletionTime(event)
+		res = append(res, n)
+	}
+	return res
+}
+
+func getCompletionTime(event abci.Event) int64 {
+	if s, ok := getAttr(event, stypes.AttributeKeyCompletionTime); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.Unix()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/coinexchain/cet-sdk/modules/bankx"
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/cet-sdk/testutil"
+	dex "github.com/coinexchain/cet-sdk/types"
+)
+
+func newEvent(typ string, kvs ...string) abci.Event {
+	event := abci.Event{Type: typ}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		event.Attributes = append(event.Attributes, cmn.KVPair{Key: []byte(kvs[i]), Value: []byte(kvs[i+1])})
+	}
+	return event
+}
+
+func transferEvents(from, to, amount string) []abci.Event {
+	return []abci.Event{
+		newEvent("transfer", "recipient", to, "amount", amount),
+		newEvent("message", "sender", from),
+	}
+}
+
+func concatEvents(groups ...[]abci.Event) []abci.Event {
+	var res []abci.Event
+	for _, g := range groups {
+		res = append(res, g...)
+	}
+	return res
+}
+
+func TestDecodeMultiSendEvents(t *testing.T) {
+	events := concatEvents(
+		// InputOutputCoins
+		[]abci.Event{
+			newEvent("message", "sender", "in1"),
+			newEvent("message", "sender", "in2"),
+			newEvent("transfer", "recipient", "out1", "amount", "1cet"),
+			newEvent("transfer", "recipient", "out2", "amount", "2cet"),
+		},
+		// the activation fee of a fresh account
+		transferEvents("out2", "collector", "3cet"),
+		[]abci.Event{
+			newEvent("message", "module", "bankx"),
+			newEvent("message", "action", "multi_send"),
+		},
+	)
+	msgs := DecodeTxEvents(events)
+	require.Equal(t, 1, len(msgs))
+	require.Equal(t, "multi_send", msgs[0].Action)
+	require.Equal(t, 1, len(msgs[0].Modules))
+	require.Equal(t, "bankx", msgs[0].Modules[0].Module)
+	require.Equal(t, []TransferRecord{
+		{Sender: "in1,in2", Recipient: "out1", Amount: "1cet"},
+		{Sender: "in1,in2", Recipient: "out2", Amount: "2cet"},
+		{Sender: "out2", Recipient: "collector", Amount: "3cet"},
+	}, msgs[0].Modules[0].Transfers())
+
+	// a single input
+	events = []abci.Event{
+		newEvent("message", "sender", "in1"),
+		newEvent("transfer", "recipient", "out1", "amount", "1cet"),
+		newEvent("transfer", "recipient", "out2", "amount", "2cet"),
+		newEvent("message", "action", "multi_send"),
+	}
+	msgs = DecodeTxEvents(events)
+	require.Equal(t, "", msgs[0].Modules[0].Module)
+	require.Equal(t, []TransferRecord{
+		{Sender: "in1", Recipient: "out1", Amount: "1cet"},
+		{Sender: "in1", Recipient: "out2", Amount: "2cet"},
+	}, msgs[0].Modules[0].Transfers())
+}
+
+func TestDecodeMixedMsgEvents(t *testing.T) {
+	completion := time.Unix(1600000000, 0).UTC()
+	events := concatEvents(
+		transferEvents("alice", "bob", "10cet"),
+		[]abci.Event{
+			newEvent("message", "module", "bankx", "sender", "alice"),
+			newEvent("message", "action", "send"),
+		},
+		// the rewards are withdrawn and the tokens are moved between the pools before unbonding
+		transferEvents("distr", "alice", "1cet"),
+		[]abci.Event{newEvent("withdraw_rewards", "amount", "1cet", "validator", "val1")},
+		transferEvents("bonded", "not_bonded", "5cet"),
+		[]abci.Event{
+			newEvent("unbond", "validator", "val1", "amount", "5", "completion_time", completion.Format(time.RFC3339)),
+			newEvent("extra"),
+			newEvent("message", "module", "staking", "sender", "alice"),
+			newEvent("message", "action", "begin_unbonding"),
+			newEvent("redelegate", "source_validator", "val1", "destination_validator", "val2",
+				"amount", "3", "completion_time", completion.Format(time.RFC3339)),
+			newEvent("message", "module", "staking", "sender", "alice"),
+			newEvent("message", "action", "begin_redelegate"),
+		},
+	)
+	msgs := DecodeTxEvents(events)
+	require.Equal(t, 3, len(msgs))
+	for i, action := range []string{"send", "begin_unbonding", "begin_redelegate"} {
+		require.Equal(t, i, msgs[i].Index)
+		require.Equal(t, action, msgs[i].Action)
+		require.Equal(t, 1, len(msgs[i].Modules))
+	}
+
+	require.Equal(t, []TransferRecord{{Sender: "alice", Recipient: "bob", Amount: "10cet"}}, msgs[0].Modules[0].Transfers())
+	require.Empty(t, msgs[0].Modules[0].BeginUnbondings())
+
+	staking := msgs[1].Modules[0]
+	require.Equal(t, "staking", staking.Module)
+	require.Equal(t, "alice", staking.Sender)
+	require.Equal(t, []TransferRecord{
+		{Sender: "distr", Recipient: "alice", Amount: "1cet"},
+		{Sender: "bonded", Recipient: "not_bonded", Amount: "5cet"},
+	}, staking.Transfers())
+	require.Equal(t, []NotificationBeginUnbonding{{
+		Version: BeginUnbondingVersion, Delegator: "alice", Validator: "val1", Amount: "5", CompletionTime: completion.Unix(),
+	}}, staking.BeginUnbondings())
+	require.Empty(t, staking.BeginRedelegations())
+
+	require.Equal(t, []NotificationBeginRedelegation{{
+		Version: BeginRedelegationVersion, Delegator: "alice", ValidatorSrc: "val1", ValidatorDst: "val2",
+		Amount: "3", CompletionTime: completion.Unix(),
+	}}, msgs[2].Modules[0].BeginRedelegations())
+}
+
+func TestNotifyMultiSendTx(t *testing.T) {
+	key1, _, fromAddr1 := testutil.KeyPubAddr()
+	key2, _, fromAddr2 := testutil.KeyPubAddr()
+	_, _, toAddr1 := testutil.KeyPubAddr()
+	_, _, toAddr2 := testutil.KeyPubAddr()
+	coins := sdk.NewCoins(sdk.NewInt64Coin("cet", 30000000000))
+	acc0 := auth.BaseAccount{Address: fromAddr1, Coins: coins}
+	acc1 := auth.BaseAccount{Address: fromAddr2, Coins: coins}
+	acc2 := auth.BaseAccount{Address: toAddr1, Coins: dex.NewCetCoins(0)}
+
+	app := initAppWithBaseAccounts(acc0, acc1, acc2)
+	app.msgQueProducer = msgqueue.NewProducerFromConfig([]string{"nop"}, "bankx", true, nil)
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+
+	coins = dex.NewCetCoins(1000000000)
+	in := []bank.Input{bank.NewInput(fromAddr1, coins), bank.NewInput(fromAddr2, coins)}
+	out := []bank.Output{bank.NewOutput(toAddr1, coins), bank.NewOutput(toAddr2, coins)}
+	tx := newStdTxBuilder().
+		Msgs(bankx.NewMsgMultiSend(in, out)).GasAndFee(1000000, 100).
+		AccNumSeqKey(0, 0, key1).AccNumSeqKey(1, 0, key2).Build()
+	require.Equal(t, sdk.CodeOK, app.Deliver(tx).Code)
+
+	var n4s NotificationTx
+	for _, msg := range app.pubMsgs {
+		if string(msg.Key) == KeyNotifyTx {
+			require.Nil(t, json.Unmarshal(msg.encoded().Value, &n4s))
+		}
+	}
+	inputs := fromAddr1.String() + "," + fromAddr2.String()
+	require.Equal(t, NotifyTxVersion, n4s.Version)
+	require.Equal(t, 3, len(n4s.Transfers))
+	require.Equal(t, TransferRecord{Sender: inputs, Recipient: toAddr1.String(), Amount: coins.String()}, n4s.Transfers[0])
+	require.Equal(t, TransferRecord{Sender: inputs, Recipient: toAddr2.String(), Amount: coins.String()}, n4s.Transfers[1])
+	// the activation fee of toAddr2
+	require.Equal(t, toAddr2.String(), n4s.Transfers[2].Sender)
+}
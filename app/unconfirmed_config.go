@@ -0,0 +1,189 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/dex/app/admin"
+)
+
+// flags (or keys in app.toml) for limiting the unconfirmed txs of each account
+const (
+	FlagUnconfirmedTxLimitTime      = "unconfirmed-tx-limit-time"
+	FlagUnconfirmedTxMaxExpired     = "unconfirmed-tx-max-expired-per-commit"
+	FlagUnconfirmedTxQuota          = "unconfirmed-tx-quota"
+	FlagUnconfirmedTxQuotaOverrides = "unconfirmed-tx-quota-overrides"
+	FlagUnconfirmedTxFeeTiers       = "unconfirmed-tx-fee-tiers"
+)
+
+// RouteUnconfirmedLimit is the admin route to get or update UnconfirmedLimitConfig
+const RouteUnconfirmedLimit = "/unconfirmed-limit"
+
+// UnconfirmedLimitConfig configures how many unconfirmed txs an account can have in the mempool
+type UnconfirmedLimitConfig struct {
+	// seconds before an unconfirmed tx stops blocking the other txs of its signers, non-positive disables the limit
+	LimitTime int64 `json:"limit_time"`
+	// expired txs removed from the memory in a commit at most
+	MaxExpiredPerCommit int64 `json:"max_expired_per_commit"`
+	// unconfirmed txs allowed for an account, UnlimitedQuota means no limit
+	Quota int `json:"quota"`
+	// per-account quotas like "addr:5", which override Quota
+	QuotaOverrides []string `json:"quota_overrides"`
+	// quotas like "10:2" for the txs paying at least 10 times the min gas price, which raise the account's quota
+	FeeTiers []string `json:"fee_tiers"`
+}
+
+// UnconfirmedLimitStatus is returned by RouteUnconfirmedLimit. An update is pending until the next commit.
+type UnconfirmedLimitStatus struct {
+	Current UnconfirmedLimitConfig  `json:"current"`
+	Pending *UnconfirmedLimitConfig `json:"pending,omitempty"`
+}
+
+func DefaultUnconfirmedLimitConfig() UnconfirmedLimitConfig {
+	return UnconfirmedLimitConfig{
+		LimitTime:           DefaultLimitTime,
+		MaxExpiredPerCommit: DefaultMaxExpiredPerCommit,
+		Quota:               DefaultQuota,
+	}
+}
+
+// UnconfirmedLimitConfigFromViper reads the config from flags or app.toml, the missing values are set to the defaults
+func UnconfirmedLimitConfigFromViper() UnconfirmedLimitConfig {
+	cfg := DefaultUnconfirmedLimitConfig()
+	if viper.IsSet(FlagUnconfirmedTxLimitTime) {
+		cfg.LimitTime = viper.GetInt64(FlagUnconfirmedTxLimitTime)
+	}
+	if viper.IsSet(FlagUnconfirmedTxMaxExpired) {
+		cfg.MaxExpiredPerCommit = viper.GetInt64(FlagUnconfirmedTxMaxExpired)
+	}
+	if viper.IsSet(FlagUnconfirmedTxQuota) {
+		cfg.Quota = viper.GetInt(FlagUnconfirmedTxQuota)
+	}
+	cfg.QuotaOverrides = viper.GetStringSlice(FlagUnconfirmedTxQuotaOverrides)
+	cfg.FeeTiers = viper.GetStringSlice(FlagUnconfirmedTxFeeTiers)
+	return cfg
+}
+
+// EnvUnconfirmedTxLimitTime is the deprecated environment variable for FlagUnconfirmedTxLimitTime,
+// which is still used if the flag is not set. As before, a value which is not an integer disables the limit.
+const EnvUnconfirmedTxLimitTime = "COINEX_UNCONFIRMED_TX_LIMIT_TIME"
+
+// unconfirmedLimitConfigWithEnv is UnconfirmedLimitConfigFromViper with EnvUnconfirmedTxLimitTime as a fallback
+func unconfirmedLimitConfigWithEnv(logger log.Logger) UnconfirmedLimitConfig {
+	cfg := UnconfirmedLimitConfigFromViper()
+	s, ok := os.LookupEnv(EnvUnconfirmedTxLimitTime)
+	if !ok {
+		return cfg
+	}
+	if viper.IsSet(FlagUnconfirmedTxLimitTime) {
+		logger.Error(fmt.Sprintf("%s is deprecated and ignored, because %s is set",
+			EnvUnconfirmedTxLimitTime, FlagUnconfirmedTxLimitTime))
+		return cfg
+	}
+	limitTime, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		limitTime = -1
+	}
+	cfg.LimitTime = limitTime
+	logger.Error(fmt.Sprintf("%s is deprecated, please set %s in app.toml instead",
+		EnvUnconfirmedTxLimitTime, FlagUnconfirmedTxLimitTime), "limit_time", limitTime)
+	return cfg
+}
+
+func (cfg UnconfirmedLimitConfig) Enabled() bool {
+	return cfg.LimitTime > 0
+}
+
+func (cfg UnconfirmedLimitConfig) Validate() error {
+	if cfg.MaxExpiredPerCommit <= 0 {
+		return fmt.Errorf("%s must be positive", FlagUnconfirmedTxMaxExpired)
+	}
+	if cfg.Quota < 0 {
+		return fmt.Errorf("%s must not be negative", FlagUnconfirmedTxQuota)
+	}
+	if _, err := parseQuotaOverrides(cfg.QuotaOverrides); err != nil {
+		return fmt.Errorf("invalid %s: %s", FlagUnconfirmedTxQuotaOverrides, err.Error())
+	}
+	if _, err := parseFeeTiers(cfg.FeeTiers); err != nil {
+		return fmt.Errorf("invalid %s: %s", FlagUnconfirmedTxFeeTiers, err.Error())
+	}
+	return nil
+}
+
+// applyUnconfirmedLimitConfig must be called when neither CheckTx nor DeliverTx is running,
+// i.e. during initialization or in Commit
+func (app *CetChainApp) applyUnconfirmedLimitConfig(cfg UnconfirmedLimitConfig) {
+	// validated before
+	quotas, _ := parseQuotaOverrides(cfg.QuotaOverrides)
+	feeTiers, _ := parseFeeTiers(cfg.FeeTiers)
+	if cfg.Enabled() && !app.enableUnconfirmedLimit {
+		// the txs seen while the limit is disabled are not tracked, so start afresh
+		app.account2UnconfirmedTx.Reset()
+	}
+	app.account2UnconfirmedTx.SetLimitTime(cfg.LimitTime)
+	app.account2UnconfirmedTx.SetMaxExpiredPerCommit(cfg.MaxExpiredPerCommit)
+	app.account2UnconfirmedTx.SetQuotas(cfg.Quota, quotas)
+	app.account2UnconfirmedTx.SetFeeTiers(feeTiers)
+	app.enableUnconfirmedLimit = cfg.Enabled()
+
+	app.unconfirmedLimitMtx.Lock()
+	defer app.unconfirmedLimitMtx.Unlock()
+	app.unconfirmedLimitConfig = cfg
+}
+
+// UpdateUnconfirmedLimitConfig validates cfg, which takes effect after the next commit
+func (app *CetChainApp) UpdateUnconfirmedLimitConfig(cfg UnconfirmedLimitConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	app.unconfirmedLimitMtx.Lock()
+	defer app.unconfirmedLimitMtx.Unlock()
+	app.pendingUnconfirmedLimitConfig = &cfg
+	return nil
+}
+
+func (app *CetChainApp) GetUnconfirmedLimitStatus() UnconfirmedLimitStatus {
+	app.unconfirmedLimitMtx.Lock()
+	defer app.unconfirmedLimitMtx.Unlock()
+	return UnconfirmedLimitStatus{
+		Current: app.unconfirmedLimitConfig,
+		Pending: app.pendingUnconfirmedLimitConfig,
+	}
+}
+
+func (app *CetChainApp) applyPendingUnconfirmedLimitConfig() {
+	app.unconfirmedLimitMtx.Lock()
+	pending := app.pendingUnconfirmedLimitConfig
+	app.pendingUnconfirmedLimitConfig = nil
+	app.unconfirmedLimitMtx.Unlock()
+
+	if pending != nil {
+		app.applyUnconfirmedLimitConfig(*pending)
+		app.Logger().Info(fmt.Sprintf("unconfirmed limit config is updated: %+v", *pending))
+	}
+}
+
+func (app *CetChainApp) registerUnconfirmedLimitRoutes(r *mux.Router) {
+	r.HandleFunc(RouteUnconfirmedLimit, func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, app.GetUnconfirmedLimitStatus())
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc(RouteUnconfirmedLimit, func(w http.ResponseWriter, r *http.Request) {
+		var cfg UnconfirmedLimitConfig
+		if err := admin.ReadJSON(r, &cfg); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := app.UpdateUnconfirmedLimitConfig(cfg); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		admin.WriteJSON(w, app.GetUnconfirmedLimitStatus())
+	}).Methods(http.MethodPost)
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/testutil"
+)
+
+func TestUnconfirmedLimitConfig(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+
+	cfg := DefaultUnconfirmedLimitConfig()
+	require.Nil(t, cfg.Validate())
+	require.True(t, cfg.Enabled())
+
+	cfg.LimitTime = 0
+	require.Nil(t, cfg.Validate())
+	require.False(t, cfg.Enabled())
+
+	cfg.MaxExpiredPerCommit = 0
+	require.NotNil(t, cfg.Validate())
+	cfg.MaxExpiredPerCommit = 10
+	cfg.Quota = -1
+	require.NotNil(t, cfg.Validate())
+	cfg.Quota = 2
+	cfg.QuotaOverrides = []string{addr.String() + ":x"}
+	require.NotNil(t, cfg.Validate())
+	cfg.QuotaOverrides = []string{addr.String() + ":3"}
+	require.Nil(t, cfg.Validate())
+}
+
+func TestUnconfirmedLimitConfigFromViper(t *testing.T) {
+	defer viper.Reset()
+	_, _, addr := testutil.KeyPubAddr()
+
+	require.Equal(t, DefaultUnconfirmedLimitConfig(), UnconfirmedLimitConfigFromViper())
+
+	viper.Set(FlagUnconfirmedTxLimitTime, 30)
+	viper.Set(FlagUnconfirmedTxMaxExpired, 120)
+	viper.Set(FlagUnconfirmedTxQuota, 0)
+	viper.Set(FlagUnconfirmedTxQuotaOverrides, []string{addr.String() + ":3"})
+	cfg := UnconfirmedLimitConfigFromViper()
+	require.Equal(t, UnconfirmedLimitConfig{
+		LimitTime:           30,
+		MaxExpiredPerCommit: 120,
+		Quota:               UnlimitedQuota,
+		QuotaOverrides:      []string{addr.String() + ":3"},
+	}, cfg)
+}
+
+func TestUnconfirmedLimitTimeFromEnv(t *testing.T) {
+	defer viper.Reset()
+	defer os.Unsetenv(EnvUnconfirmedTxLimitTime)
+	logger := log.NewNopLogger()
+
+	require.Nil(t, os.Setenv(EnvUnconfirmedTxLimitTime, "30"))
+	require.EqualValues(t, 30, unconfirmedLimitConfigWithEnv(logger).LimitTime)
+	require.Nil(t, os.Setenv(EnvUnconfirmedTxLimitTime, "off"))
+	require.False(t, unconfirmedLimitConfigWithEnv(logger).Enabled())
+
+	// the flag wins
+	viper.Set(FlagUnconfirmedTxLimitTime, 40)
+	require.EqualValues(t, 40, unconfirmedLimitConfigWithEnv(logger).LimitTime)
+
+	viper.Set(FlagUnconfirmedTxLimitTime, nil)
+	app := initAppWithBaseAccounts()
+	require.False(t, app.enableUnconfirmedLimit)
+}
+
+func TestUpdateUnconfirmedLimitConfig(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	app := initAppWithBaseAccounts(auth.BaseAccount{Address: addr})
+	require.True(t, app.enableUnconfirmedLimit)
+
+	cfg := DefaultUnconfirmedLimitConfig()
+	cfg.MaxExpiredPerCommit = -1
+	require.NotNil(t, app.UpdateUnconfirmedLimitConfig(cfg))
+	require.Nil(t, app.GetUnconfirmedLimitStatus().Pending)
+
+	// disable the limit, which takes effect after commit
+	cfg.MaxExpiredPerCommit = DefaultMaxExpiredPerCommit
+	cfg.LimitTime = 0
+	require.Nil(t, app.UpdateUnconfirmedLimitConfig(cfg))
+	require.Equal(t, cfg, *app.GetUnconfirmedLimitStatus().Pending)
+	require.True(t, app.enableUnconfirmedLimit)
+
+	header := abci.Header{Height: 1, Time: time.Now(), ChainID: testChainID}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+	require.False(t, app.enableUnconfirmedLimit)
+	require.Equal(t, UnconfirmedLimitStatus{Current: cfg}, app.GetUnconfirmedLimitStatus())
+
+	// enable it again with a larger quota for addr
+	cfg.LimitTime = 100
+	cfg.QuotaOverrides = []string{addr.String() + ":5"}
+	require.Nil(t, app.UpdateUnconfirmedLimitConfig(cfg))
+	header.Height = 2
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	app.Commit()
+	require.True(t, app.enableUnconfirmedLimit)
+	require.Equal(t, int64(100), app.account2UnconfirmedTx.getLimitTime())
+	require.Equal(t, 5, app.account2UnconfirmedTx.getQuota(addr))
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The unconfirmed-tx tracker lives in the memory of each node, so the results of these queries
+// are node-local: they are not part of the state and not backed by any proof.
+const (
+	UnconfirmedQuerierRoute = "unconfirmed"
+	QueryUnconfirmedTxs     = "txs"
+	QueryUnconfirmedStats   = "stats"
+)
+
+type QueryUnconfirmedTxsParam struct {
+	Address sdk.AccAddress `json:"address"`
+}
+
+// UnconfirmedTxInfo describes an unconfirmed tx of an account, times are unix seconds
+type UnconfirmedTxInfo struct {
+	HashID     string `json:"hash_id"`
+	Sequence   uint64 `json:"sequence"`
+	AddedTime  int64  `json:"added_time"`
+	ExpireTime int64  `json:"expire_time"`
+	Expired    bool   `json:"expired"`
+}
+
+// UnconfirmedTxsOfAccount is the result of QueryUnconfirmedTxs
+type UnconfirmedTxsOfAccount struct {
+	Address sdk.AccAddress      `json:"address"`
+	Quota   int                 `json:"quota"`
+	Txs     []UnconfirmedTxInfo `json:"txs"`
+}
+
+// UnconfirmedLimitStats is the result of QueryUnconfirmedStats
+type UnconfirmedLimitStats struct {
+	Enabled       bool  `json:"enabled"`
+	LimitTime     int64 `json:"limit_time"`
+	CurrBlockTime int64 `json:"curr_block_time"`
+	UnconfirmedTxStats
+}
+
+func (app *CetChainApp) newUnconfirmedQuerier() sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		switch path[0] {
+		case QueryUnconfirmedTxs:
+			return app.queryUnconfirmedTxs(req)
+		case QueryUnconfirmedStats:
+			return app.queryUnconfirmedStats()
+		default:
+			return nil, sdk.ErrUnknownRequest("unknown unconfirmed query endpoint: " + path[0])
+		}
+	}
+}
+
+func (app *CetChainApp) queryUnconfirmedTxs(req abci.RequestQuery) ([]byte, sdk.Error) {
+	var param QueryUnconfirmedTxsParam
+	if err := app.cdc.UnmarshalJSON(req.Data, &param); err != nil {
+		return nil, sdk.ErrUnknownRequest("failed to parse param: " + err.Error())
+	}
+	if param.Address.Empty() {
+		return nil, sdk.ErrInvalidAddress("address is missing")
+	}
+
+	acc2unc := app.account2UnconfirmedTx
+	res := UnconfirmedTxsOfAccount{
+		Address: param.Address,
+		Quota:   acc2unc.getQuota(param.Address),
+		Txs:     []UnconfirmedTxInfo{},
+	}
+	for _, unconfirmedTx := range acc2unc.GetTxs(param.Address) {
+		res.Txs = append(res.Txs, UnconfirmedTxInfo{
+			HashID:     fmt.Sprintf("%X", unconfirmedTx.HashID),
+			Sequence:   unconfirmedTx.Sequence,
+			AddedTime:  unconfirmedTx.Timestamp,
+			ExpireTime: acc2unc.ExpireTime(unconfirmedTx),
+			Expired:    acc2unc.isExpired(unconfirmedTx, app.currBlockTime),
+		})
+	}
+	return marshalQueryResult(res)
+}
+
+func (app *CetChainApp) queryUnconfirmedStats() ([]byte, sdk.Error) {
+	return marshalQueryResult(UnconfirmedLimitStats{
+		Enabled:            app.GetUnconfirmedLimitStatus().Current.Enabled(),
+		LimitTime:          app.account2UnconfirmedTx.getLimitTime(),
+		CurrBlockTime:      app.currBlockTime,
+		UnconfirmedTxStats: app.account2UnconfirmedTx.GetStats(),
+	})
+}
+
+func marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	return bz, nil
+}
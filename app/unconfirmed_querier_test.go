@@ -0,0 +1,61 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/coinexchain/cet-sdk/testutil"
+)
+
+func TestQueryUnconfirmedTxs(t *testing.T) {
+	_, _, addr := testutil.KeyPubAddr()
+	app := initAppWithBaseAccounts(auth.BaseAccount{Address: addr})
+	now := time.Now()
+	// custom queries need a committed height
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1, Time: now, ChainID: testChainID}})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+	app.account2UnconfirmedTx.Add(addr, []byte{0xab, 0xcd}, 0, now.Unix()-DefaultLimitTime-1)
+	app.account2UnconfirmedTx.Add(addr, []byte{0x12}, 1, now.Unix())
+	app.account2UnconfirmedTx.AddToRemoveList(addr, []byte{0x34}, 5)
+
+	param, err := app.cdc.MarshalJSON(QueryUnconfirmedTxsParam{Address: addr})
+	require.Nil(t, err)
+	res := app.Query(abci.RequestQuery{Path: fmt.Sprintf("custom/%s/%s", UnconfirmedQuerierRoute, QueryUnconfirmedTxs), Data: param})
+	require.True(t, res.IsOK(), res.Log)
+	var txs UnconfirmedTxsOfAccount
+	require.Nil(t, json.Unmarshal(res.Value, &txs))
+	require.Equal(t, addr, txs.Address)
+	require.Equal(t, DefaultQuota, txs.Quota)
+	require.Equal(t, []UnconfirmedTxInfo{
+		{HashID: "ABCD", Sequence: 0, AddedTime: now.Unix() - DefaultLimitTime - 1, ExpireTime: now.Unix() - 1, Expired: true},
+		{HashID: "12", Sequence: 1, AddedTime: now.Unix(), ExpireTime: now.Unix() + DefaultLimitTime, Expired: false},
+	}, txs.Txs)
+
+	res = app.Query(abci.RequestQuery{Path: fmt.Sprintf("custom/%s/%s", UnconfirmedQuerierRoute, QueryUnconfirmedTxs)})
+	require.False(t, res.IsOK())
+
+	res = app.Query(abci.RequestQuery{Path: fmt.Sprintf("custom/%s/%s", UnconfirmedQuerierRoute, QueryUnconfirmedStats)})
+	require.True(t, res.IsOK(), res.Log)
+	var stats UnconfirmedLimitStats
+	require.Nil(t, json.Unmarshal(res.Value, &stats))
+	require.Equal(t, UnconfirmedLimitStats{
+		Enabled:       true,
+		LimitTime:     DefaultLimitTime,
+		CurrBlockTime: now.Unix(),
+		UnconfirmedTxStats: UnconfirmedTxStats{
+			Accounts:      1,
+			Txs:           2,
+			ExpiryQueue:   2,
+			LastSweepTime: now.Unix(),
+			RemoveListLen: 1,
+		},
+	}, stats)
+}
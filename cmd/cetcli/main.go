@@ -138,6 +138,8 @@ func queryCmd(cdc *codec.Codec) *cobra.Command {
 		authcmd.QueryTxsByEventsCmd(cdc),
 		authcmd.QueryTxCmd(cdc),
 		client.LineBreak,
+		unconfirmedQueryCmd(cdc),
+		client.LineBreak,
 	)
 
 	// add modules' query commands
@@ -188,6 +190,7 @@ func registerRoutes(rs *lcd.RestServer) {
 	client.RegisterRoutes(rs.CliCtx, rs.Mux)
 	authrest.RegisterTxRoutes(rs.CliCtx, rs.Mux)
 	app.ModuleBasics.RegisterRESTRoutes(rs.CliCtx, rs.Mux)
+	registerUnconfirmedRoutes(rs.CliCtx, rs.Mux)
 }
 
 func fixDescriptions(cmd *cobra.Command) {
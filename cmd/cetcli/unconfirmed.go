@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	"github.com/coinexchain/dex/app"
+)
+
+var (
+	unconfirmedTxsPath   = fmt.Sprintf("custom/%s/%s", app.UnconfirmedQuerierRoute, app.QueryUnconfirmedTxs)
+	unconfirmedStatsPath = fmt.Sprintf("custom/%s/%s", app.UnconfirmedQuerierRoute, app.QueryUnconfirmedStats)
+)
+
+func unconfirmedQueryCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unconfirmed",
+		Short: "Query the unconfirmed txs tracked by the connected node",
+	}
+	cmd.AddCommand(client.GetCommands(
+		unconfirmedTxsCmd(cdc),
+		unconfirmedStatsCmd(cdc),
+	)...)
+	return cmd
+}
+
+func unconfirmedTxsCmd(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "txs [address]",
+		Short: "Query the unconfirmed txs of an account, which are counted against its quota",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(app.QueryUnconfirmedTxsParam{Address: addr})
+			if err != nil {
+				return err
+			}
+			res, _, err := context.NewCLIContext().WithCodec(cdc).QueryWithData(unconfirmedTxsPath, bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func unconfirmedStatsCmd(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Query the statistics of the unconfirmed-tx tracker",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, _, err := context.NewCLIContext().WithCodec(cdc).QueryWithData(unconfirmedStatsPath, nil)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func registerUnconfirmedRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc("/unconfirmed/txs/{address}", unconfirmedTxsHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/unconfirmed/stats", unconfirmedStatsHandlerFn(cliCtx)).Methods("GET")
+}
+
+func unconfirmedTxsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr, err := sdk.AccAddressFromBech32(mux.Vars(r)["address"])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(app.QueryUnconfirmedTxsParam{Address: addr})
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, height, err := cliCtx.QueryWithData(unconfirmedTxsPath, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx.WithHeight(height), res)
+	}
+}
+
+func unconfirmedStatsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, height, err := cliCtx.QueryWithData(unconfirmedStatsPath, nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx.WithHeight(height), res)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/server"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/dex/app"
+)
+
+const (
+	flagSnapshot = "snapshot"
+	flagGenesis  = "genesis"
+	flagSegment  = "segment"
+	flagEncoding = "encoding"
+)
+
+func backfillMsgsCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill-msgs",
+		Short: "Regenerate the PubMsgs of past heights by replaying the local block store",
+		Long: `Regenerate the PubMsgs of past heights by replaying the blocks in the local block store, and write
+them into the files of a file sink. The replay starts from a snapshot, which is a copy of the data dir
+holding application.db, or from the genesis file if no snapshot is given. The snapshot is copied before
+replaying, so it is left untouched. The msgqueue feature toggle is forced on, and subscribe-modules in
+app.toml decides the msgs to generate, just like a live node. The node must be stopped, because the
+block store is locked by it.
+
+An export of the app state (cetd export) can not be used as the start, unless it is the genesis file
+of the chain. The IAVL trees imported from an export have other versions than the ones of the chain,
+so the app hashes of the replayed blocks can not be checked against the block store, and the msgs
+are not guaranteed to be what a live node sent. Keep a copy of the data dir as a snapshot instead.
+
+$ cetd backfill-msgs --output=/data/pubmsgs --to-height=100000
+$ cetd backfill-msgs --snapshot=/backup/data --from-height=50001 --to-height=60000 --output=/data/pubmsgs
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := viper.GetString(flagOutput)
+			if output == "" {
+				return fmt.Errorf("--%s is required", flagOutput)
+			}
+			sink, err := app.NewFileSink(output, viper.GetInt64(flagSegment), app.SinkEncoding(viper.GetString(flagEncoding)))
+			if err != nil {
+				return err
+			}
+			defer sink.Close()
+			return backfillMsgs(ctx, sink)
+		},
+	}
+	cmd.Flags().String(flagSnapshot, "", "The data dir holding the application.db to start from, the genesis file is used if omitted")
+	cmd.Flags().String(flagGenesis, "", "The genesis file to start from without a snapshot, the one of the node if omitted")
+	cmd.Flags().Int64(flagFromHeight, 0, "The first height whose msgs are written, the one after the snapshot if omitted")
+	cmd.Flags().Int64(flagToHeight, 0, "The last height to replay, the latest one in the block store if omitted")
+	cmd.Flags().String(flagOutput, "", "The dir of the file sink")
+	cmd.Flags().Int64(flagSegment, app.DefaultSegmentHeights, "The number of heights in a file of the file sink")
+	cmd.Flags().String(flagEncoding, string(app.SinkEncodingJSON), "The encoding of the file sink, json or codon")
+	return cmd
+}
+
+func backfillMsgs(ctx *server.Context, sink app.PubMsgSink) error {
+	cfg := ctx.Config
+	backend := dbm.DBBackendType(cfg.DBBackend)
+	blockStoreDB, err := openDB("blockstore", backend, cfg.DBDir())
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	blockStore := store.NewBlockStore(blockStoreDB)
+	stateDB, err := openDB("state", backend, cfg.DBDir())
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	workDir, err := ioutil.TempDir("", "backfill-msgs")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+	if snapshot := viper.GetString(flagSnapshot); snapshot != "" {
+		if err = copyDir(filepath.Join(snapshot, "application.db"), filepath.Join(workDir, "application.db")); err != nil {
+			return err
+		}
+	}
+	appDB, err := openDB("application", backend, workDir)
+	if err != nil {
+		return err
+	}
+	defer appDB.Close()
+
+	if len(viper.GetString(msgqueue.FlagTopics)) == 0 {
+		return fmt.Errorf("%s is not configured in app.toml, no msg would be generated", msgqueue.FlagTopics)
+	}
+	// no msg is sent to the brokers of app.toml, and the embedded trade-server, which is only started
+	// with a "prune:" broker, is kept off
+	viper.Set(msgqueue.FlagBrokers, []string{"nop"})
+	viper.Set(msgqueue.FlagFeatureToggle, true)
+	viper.Set(app.FlagPubMsgSinks, []string{})
+	viper.Set(app.FlagFailedTxEventSinks, []string{})
+	viper.Set(app.FlagPubMsgOutboxRetention, 0)
+	// the last unlock time of the node is kept as it is
+	viper.Set(app.FlagLocalDBDir, workDir)
+	// the blocks are sent by Commit, so none is left in the queue when the backfill returns
+	viper.Set(app.FlagPubMsgQueueSize, 0)
+	logger := ctx.Logger
+	cetApp := app.NewCetChainApp(logger, appDB, nil, true, 0)
+
+	client, err := proxy.NewLocalClientCreator(cetApp).NewABCIClient()
+	if err != nil {
+		return err
+	}
+	if err = client.Start(); err != nil {
+		return err
+	}
+	defer client.Stop()
+	conn := proxy.NewAppConnConsensus(client)
+
+	lastHeight := cetApp.LastBlockHeight()
+	if lastHeight == tmtypes.GenesisBlockHeight {
+		if err = initChainFromGenesis(conn, viper.GetString(flagGenesis), cfg.GenesisFile()); err != nil {
+			return err
+		}
+	}
+
+	from, to := viper.GetInt64(flagFromHeight), viper.GetInt64(flagToHeight)
+	if from == 0 {
+		from = lastHeight + 1
+	}
+	if to == 0 {
+		to = blockStore.Height()
+	}
+	if from <= lastHeight || from > to || to > blockStore.Height() {
+		return fmt.Errorf("can not backfill [%d, %d], the app state is at height %d and the block store at %d",
+			from, to, lastHeight, blockStore.Height())
+	}
+
+	for h := lastHeight + 1; h <= to; h++ {
+		if h == from {
+			if err = cetApp.AddPubMsgSink(sink, app.SinkPolicyBlock, 0); err != nil {
+				return err
+			}
+		}
+		if err = execBlock(conn, blockStore, stateDB, h, logger); err != nil {
+			return err
+		}
+		if h%1000 == 0 {
+			logger.Info(fmt.Sprintf("backfilled height %d", h))
+		}
+	}
+	return nil
+}
+
+// execBlock replays a block as the node does in the handshake, and makes sure the app hash is the one
+// agreed by the next block, so the msgs are the same as what a live node sent
+func execBlock(conn proxy.AppConnConsensus, blockStore *store.BlockStore, stateDB dbm.DB, height int64, logger log.Logger) error {
+	block := blockStore.LoadBlock(height)
+	if block == nil {
+		return fmt.Errorf("block %d is not in the block store", height)
+	}
+	appHash, err := sm.ExecCommitBlock(conn, block, logger, stateDB)
+	if err != nil {
+		return err
+	}
+	if next := blockStore.LoadBlockMeta(height + 1); next != nil && !bytes.Equal(next.Header.AppHash, appHash) {
+		return fmt.Errorf("app hash mismatch at height %d: %X != %X, the snapshot or the genesis file is not of this chain",
+			height, appHash, next.Header.AppHash)
+	}
+	return nil
+}
+
+// initChainFromGenesis sends InitChain as the node does in the handshake
+func initChainFromGenesis(conn proxy.AppConnConsensus, genesisFile, defaultFile string) error {
+	if genesisFile == "" {
+		genesisFile = defaultFile
+	}
+	genDoc, err := tmtypes.GenesisDocFromFile(genesisFile)
+	if err != nil {
+		return err
+	}
+	if genDoc.GenesisBlockHeight != tmtypes.GenesisBlockHeight {
+		return fmt.Errorf("the genesis block height of %s is %d, while the node's is %d, an export can not be used",
+			genesisFile, genDoc.GenesisBlockHeight, tmtypes.GenesisBlockHeight)
+	}
+	validators := make([]*tmtypes.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		validators[i] = tmtypes.NewValidator(val.PubKey, val.Power)
+	}
+	_, err = conn.InitChainSync(abci.RequestInitChain{
+		Time:            genDoc.GenesisTime,
+		ChainId:         genDoc.ChainID,
+		ConsensusParams: tmtypes.TM2PB.ConsensusParams(genDoc.ConsensusParams),
+		Validators:      tmtypes.TM2PB.ValidatorUpdates(tmtypes.NewValidatorSet(validators)),
+		AppStateBytes:   genDoc.AppState,
+	})
+	return err
+}
+
+// openDB returns an error instead of panicking, such as when the db is locked by a running node
+func openDB(name string, backend dbm.DBBackendType, dir string) (db dbm.DB, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			db, err = nil, fmt.Errorf("open %s failed: %v", name, r)
+		}
+	}()
+	return dbm.NewDB(name, backend, dir), nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+		// LOCK is held by the node which made the snapshot, it is created again when the copy is opened
+		if info.Name() == "LOCK" {
+			return nil
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy_dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	require.Nil(t, os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(src, "000001.ldb"), []byte("a"), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(src, "sub", "MANIFEST"), []byte("b"), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(src, "LOCK"), nil, 0644))
+
+	dst := filepath.Join(dir, "dst")
+	require.Nil(t, copyDir(src, dst))
+	bz, err := ioutil.ReadFile(filepath.Join(dst, "000001.ldb"))
+	require.Nil(t, err)
+	require.Equal(t, "a", string(bz))
+	bz, err = ioutil.ReadFile(filepath.Join(dst, "sub", "MANIFEST"))
+	require.Nil(t, err)
+	require.Equal(t, "b", string(bz))
+	_, err = os.Stat(filepath.Join(dst, "LOCK"))
+	require.True(t, os.IsNotExist(err))
+
+	require.NotNil(t, copyDir(filepath.Join(dir, "none"), dst))
+}
@@ -23,13 +23,16 @@ func init() {
 
 func TestCreateRootCmd(t *testing.T) {
 	rootCmd := createCetdCmd()
-	require.Equal(t, 16, len(rootCmd.Commands()))
+	require.Equal(t, 22, len(rootCmd.Commands()))
 }
 
 func TestNewApp(t *testing.T) {
 	db := dbm.NewMemDB()
 	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
 	viper.Set(server.FlagMinGasPrices, "20.0cet")
+	testHome := "./testhome"
+	defer os.RemoveAll(testHome)
+	viper.Set(cli.HomeFlag, testHome)
 	cet := newApp(logger, db, log.NewSyncWriter(os.Stdout))
 	value := reflect.ValueOf(cet).Interface().(*app.CetChainApp)
 	require.Equal(t, "CoinExChainApp", value.Name())
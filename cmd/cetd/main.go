@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"syscall"
 	"time"
@@ -67,7 +68,17 @@ func createCetdCmd() *cobra.Command {
 
 	addInitCommands(ctx, cdc, rootCmd)
 	rootCmd.AddCommand(client.NewCompletionCmd(rootCmd, true))
+	rootCmd.AddCommand(pluginCmd())
+	rootCmd.AddCommand(unconfirmedLimitCmd())
+	rootCmd.AddCommand(pubMsgWatchListCmd())
+	rootCmd.AddCommand(pubMsgQueueStatusCmd())
+	rootCmd.AddCommand(replayMsgsCmd())
+	rootCmd.AddCommand(backfillMsgsCmd(ctx))
 	server.AddCommands(ctx, cdc, rootCmd, newApp, exportAppStateAndTMValidators)
+	addPluginStartFlags(rootCmd)
+	addUnconfirmedLimitStartFlags(rootCmd)
+	addPubMsgStartFlags(rootCmd)
+	overrideStartCmd(ctx, rootCmd)
 
 	rootCmd.PersistentFlags().UintVar(&invCheckPeriod, flagInvCheckPeriod,
 		0, "Assert registered invariants every N blocks")
@@ -108,6 +119,9 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		baseapp.SetCheckTxWithMsgHandle(viper.GetBool(server.FlagCheckTxWithMsgHandle)),
 	)
 	checkMinGasPrice(cetChainApp, logger)
+	if err := cetChainApp.StartAdminServer(viper.GetString(cli.HomeFlag)); err != nil {
+		logger.Error(fmt.Sprintf("start admin server failed: %s", err.Error()))
+	}
 	return cetChainApp
 }
 
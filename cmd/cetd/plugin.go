@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/coinexchain/dex/app/admin"
+	"github.com/coinexchain/dex/app/plugin"
+)
+
+func pluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage the CheckTx plugins of the running node",
+	}
+	cmd.AddCommand(
+		pluginListCmd(),
+		pluginEnableCmd(),
+		pluginDisableCmd(),
+		pluginStatusCmd(),
+		pluginLoadCmd(),
+	)
+	return cmd
+}
+
+// addPluginStartFlags adds the flags for guarding plugins to the start command
+func addPluginStartFlags(rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() != "start" {
+			continue
+		}
+		cmd.Flags().Duration(plugin.FlagCallTimeout, plugin.DefaultCallTimeout,
+			"Deadline of each PreCheckTx call to a plugin")
+		cmd.Flags().Int(plugin.FlagMaxFailures, plugin.DefaultMaxFailures,
+			"Disable a plugin after it panics or misses the deadline for this many consecutive times")
+	}
+}
+
+func pluginListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the loaded plugins in pipeline order",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var infos []plugin.Info
+			if err := newAdminClient().Get(plugin.RouteList, &infos); err != nil {
+				return err
+			}
+			return printJSON(infos)
+		},
+	}
+}
+
+func pluginEnableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable [name]",
+		Short: "Enable the loaded plugin with the given name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postPluginRoute(plugin.RouteEnable, args[0])
+		},
+	}
+}
+
+func pluginDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable [name]",
+		Short: "Disable the loaded plugin with the given name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postPluginRoute(plugin.RouteDisable, args[0])
+		},
+	}
+}
+
+func pluginStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the loaded plugins and the errors met in the last loading",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var status plugin.Status
+			if err := newAdminClient().Get(plugin.RouteStatus, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+}
+
+func pluginLoadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "load",
+		Short: "Load and enable the plugins which are newly put into the plugins directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var status plugin.Status
+			if err := newAdminClient().Post(plugin.RouteLoad, nil, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+}
+
+func postPluginRoute(route, name string) error {
+	var infos []plugin.Info
+	if err := newAdminClient().Post(plugin.NamedRoute(route, name), nil, &infos); err != nil {
+		return err
+	}
+	return printJSON(infos)
+}
+
+func newAdminClient() *admin.Client {
+	return admin.NewClient(admin.SocketPath(viper.GetString(cli.HomeFlag)))
+}
+
+func printJSON(obj interface{}) error {
+	bz, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bz))
+	return nil
+}
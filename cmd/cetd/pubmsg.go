@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/coinexchain/cet-sdk/msgqueue"
+	"github.com/coinexchain/dex/app"
+)
+
+const (
+	flagFromHeight = "from-height"
+	flagToHeight   = "to-height"
+	flagStdout     = "stdout"
+)
+
+// addPubMsgStartFlags adds the flags for exporting the PubMsg stream to the start command
+func addPubMsgStartFlags(rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() != "start" {
+			continue
+		}
+		cmd.Flags().StringSlice(app.FlagPubMsgSinks, nil,
+			"Sinks of the PubMsg stream like file:<dir>?segment=10000,unix:<path>?policy=drop&encoding=codon,ws:<host:port>/<path>?policy=buffer&buffer=1000")
+		cmd.Flags().Int64(app.FlagPubMsgOutboxRetention, app.DefaultPubMsgOutboxRetention,
+			"Latest heights whose PubMsgs are kept in the outbox for replay-msgs, 0 to disable the outbox")
+		cmd.Flags().StringSlice(app.FlagPubMsgWatchList, nil,
+			"Addresses whose txs are published, all the txs are published if it is empty")
+		cmd.Flags().Int(app.FlagPubMsgQueueSize, app.DefaultPubMsgQueueSize,
+			"Committed blocks whose PubMsgs can wait to be sent in the background, 0 to send them in Commit")
+		cmd.Flags().StringSlice(app.FlagFailedTxEventSinks, nil,
+			"Sinks of the opt-in failed_tx_events channel in the format of --"+app.FlagPubMsgSinks+", which keeps the msgqueue events of the failed txs")
+	}
+}
+
+func replayMsgsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay-msgs",
+		Short: "Re-emit the PubMsgs of the given heights from the outbox",
+		Long: `Re-emit the PubMsgs of the given heights from the outbox, in the original order and with the
+"commit" msg at the end of each height. They are sent to the brokers and the pubmsg-sinks in app.toml,
+or printed with --stdout. If the node is running, which locks the outbox, the msgs are read through its
+admin socket. The codon payloads are not kept in the outbox, so the codon sinks get the notifications
+as JSON frames.
+
+$ cetd replay-msgs --from-height=100 --to-height=200
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := cmd.Flags().GetInt64(flagFromHeight)
+			if err != nil {
+				return err
+			}
+			to, err := cmd.Flags().GetInt64(flagToHeight)
+			if err != nil {
+				return err
+			}
+			if to == 0 {
+				to = from
+			}
+			replay, closeOutbox := openPubMsgOutbox()
+			defer closeOutbox()
+
+			if stdout, _ := cmd.Flags().GetBool(flagStdout); stdout {
+				return replay(from, to, func(block app.PubMsgBlock) error {
+					for _, msg := range block.Msgs {
+						fmt.Printf("%s#%s\n", msg.Key, msg.Value)
+					}
+					return nil
+				})
+			}
+			return replayToConfiguredTargets(replay, from, to)
+		},
+	}
+	cmd.Flags().Int64(flagFromHeight, 0, "The first height to replay")
+	cmd.Flags().Int64(flagToHeight, 0, "The last height to replay, the same as --from-height if omitted")
+	cmd.Flags().Bool(flagStdout, false, "Print the msgs as lines of key#value instead of sending them")
+	_ = cmd.MarkFlagRequired(flagFromHeight)
+	return cmd
+}
+
+// replayFunc is PubMsgOutbox.Replay of the local outbox or the one of the running node
+type replayFunc func(from, to int64, send func(block app.PubMsgBlock) error) error
+
+// openPubMsgOutbox opens the outbox under home, or uses the admin socket if it is locked by the running node
+func openPubMsgOutbox() (replayFunc, func()) {
+	home := viper.GetString(cli.HomeFlag)
+	outbox, openErr := app.OpenPubMsgOutbox(app.PubMsgOutboxDir(home), 0)
+	if openErr != nil {
+		client := newAdminClient()
+		return func(from, to int64, send func(block app.PubMsgBlock) error) error {
+			if err := app.ReplayPubMsgOutboxByAdmin(client, from, to, send); err != nil {
+				return fmt.Errorf("%s, and the running node can not replay it: %s", openErr.Error(), err.Error())
+			}
+			return nil
+		}, func() {}
+	}
+	return outbox.Replay, outbox.Close
+}
+
+func replayToConfiguredTargets(replay replayFunc, from, to int64) error {
+	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
+	brokers := viper.GetStringSlice(msgqueue.FlagBrokers)
+	sinkConfigs := viper.GetStringSlice(app.FlagPubMsgSinks)
+	if len(brokers) == 0 && len(sinkConfigs) == 0 {
+		return fmt.Errorf("neither %s nor %s is configured, use --%s to print the msgs",
+			msgqueue.FlagBrokers, app.FlagPubMsgSinks, flagStdout)
+	}
+
+	// the topics only filter the msgs produced by the modules, a replayed msg is always sent
+	producer := msgqueue.NewProducerFromConfig(brokers, "replay", true, logger)
+	defer producer.Close()
+	sinks := app.NewPubMsgSinks(logger)
+	defer sinks.Close()
+	for _, item := range sinkConfigs {
+		cfg, err := app.ParseSinkConfig(item)
+		if err != nil {
+			return err
+		}
+		sink, err := app.NewSink(cfg, logger)
+		if err != nil {
+			return err
+		}
+		// a replay must not lose any block
+		if err = sinks.Add(sink, app.SinkPolicyBuffer, cfg.BufferSize); err != nil {
+			return err
+		}
+	}
+
+	return replay(from, to, func(block app.PubMsgBlock) error {
+		for _, msg := range block.Msgs {
+			producer.SendMsg(msg.Key, msg.Value)
+		}
+		sinks.Send(block)
+		return nil
+	})
+}
+
+func pubMsgQueueStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pubmsg-queue-status",
+		Short: "Show how far the PubMsgs sent by the running node lag behind the chain",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var status app.PubMsgQueueStatus
+			if err := newAdminClient().Get(app.RoutePubMsgQueue, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+}
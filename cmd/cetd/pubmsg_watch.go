@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/coinexchain/dex/app"
+)
+
+const flagAddressFile = "file"
+
+func pubMsgWatchListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pubmsg-watch-list",
+		Short: "Show or update the addresses whose txs are published by the running node",
+		Long: `Show or update the addresses whose txs are published by the running node. The PubMsgs of a tx are
+published only if one of its signers, recipients or transfer participants is in the list, or the list
+is empty. The PubMsgs of blocks are always published. An update takes effect after the next commit.`,
+	}
+	cmd.AddCommand(
+		pubMsgWatchListStatusCmd(),
+		pubMsgWatchListUpdateCmd("set", "Replace the list with the given addresses",
+			func(_, addrs []string) []string { return addrs }),
+		pubMsgWatchListUpdateCmd("add", "Add the given addresses to the list",
+			func(current, addrs []string) []string { return mergeAddresses(current, addrs, false) }),
+		pubMsgWatchListUpdateCmd("remove", "Remove the given addresses from the list",
+			func(current, addrs []string) []string { return mergeAddresses(current, addrs, true) }),
+	)
+	return cmd
+}
+
+func pubMsgWatchListStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the current list, and the one to take effect after the next commit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var status app.PubMsgWatchListStatus
+			if err := newAdminClient().Get(app.RoutePubMsgWatchList, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+}
+
+func pubMsgWatchListUpdateCmd(use, short string, update func(current, addrs []string) []string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " [address]...",
+		Short: short,
+		Long: short + `. The addresses are given as arguments, or by a file
+with one address in each line. An empty list publishes all the txs, for example:
+
+$ cetd pubmsg-watch-list set --file=/dev/null
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addrs, err := getAddressesFromArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			client := newAdminClient()
+			var status app.PubMsgWatchListStatus
+			if err := client.Get(app.RoutePubMsgWatchList, &status); err != nil {
+				return err
+			}
+			wl := status.Current
+			if status.Pending != nil {
+				wl = *status.Pending
+			}
+			wl.Addresses = update(wl.Addresses, addrs)
+			if err := wl.Validate(); err != nil {
+				return err
+			}
+			if err := client.Post(app.RoutePubMsgWatchList, wl, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+	cmd.Flags().String(flagAddressFile, "", "The file of the addresses, one in each line")
+	return cmd
+}
+
+func getAddressesFromArgs(cmd *cobra.Command, args []string) ([]string, error) {
+	file, err := cmd.Flags().GetString(flagAddressFile)
+	if err != nil {
+		return nil, err
+	}
+	if file == "" && len(args) == 0 {
+		return nil, fmt.Errorf("no address is given")
+	}
+	addrs := append([]string{}, args...)
+	if file == "" {
+		return addrs, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			addrs = append(addrs, line)
+		}
+	}
+	return addrs, scanner.Err()
+}
+
+// mergeAddresses adds addrs to current or removes them from it, keeping the order and removing the duplicates
+func mergeAddresses(current, addrs []string, remove bool) []string {
+	removed := make(map[string]bool)
+	if remove {
+		for _, addr := range addrs {
+			removed[addr] = true
+		}
+		addrs = nil
+	}
+	res := make([]string, 0, len(current)+len(addrs))
+	seen := make(map[string]bool)
+	for _, addr := range append(current, addrs...) {
+		if !seen[addr] && !removed[addr] {
+			seen[addr] = true
+			res = append(res, addr)
+		}
+	}
+	return res
+}
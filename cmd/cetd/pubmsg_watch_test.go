@@ -0,0 +1,14 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAddresses(t *testing.T) {
+	current := []string{"a", "b"}
+	require.Equal(t, []string{"a", "b", "c"}, mergeAddresses(current, []string{"b", "c", "c"}, false))
+	require.Equal(t, []string{"b"}, mergeAddresses(current, []string{"a", "d"}, true))
+	require.Equal(t, []string{}, mergeAddresses(nil, []string{"a"}, true))
+}
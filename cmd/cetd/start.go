@@ -0,0 +1,122 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/p2p"
+	pvm "github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/proxy"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/coinexchain/dex/app"
+)
+
+// the flags of the start command, which are registered by server.StartCmd
+const (
+	flagWithTendermint = "with-tendermint"
+	flagTraceStore     = "trace-store"
+	flagCPUProfile     = "cpu-profile"
+)
+
+// overrideStartCmd makes the start command close the app after the node is stopped by a signal,
+// so that the PubMsg sinks are flushed and closed. The standalone mode is kept as it is.
+func overrideStartCmd(ctx *server.Context, rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() != "start" {
+			continue
+		}
+		runStandAlone := cmd.RunE
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if !viper.GetBool(flagWithTendermint) {
+				return runStandAlone(cmd, args)
+			}
+			ctx.Logger.Info("starting ABCI with Tendermint")
+			return startInProcess(ctx)
+		}
+	}
+}
+
+// startInProcess is server.startInProcess of cosmos-sdk, except that the app is closed on exit
+func startInProcess(ctx *server.Context) error {
+	cfg := ctx.Config
+
+	db, err := sdk.NewLevelDB("application", filepath.Join(cfg.RootDir, "data"))
+	if err != nil {
+		return err
+	}
+	var traceWriter io.Writer
+	if traceWriterFile := viper.GetString(flagTraceStore); traceWriterFile != "" {
+		f, err := os.OpenFile(traceWriterFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+		if err != nil {
+			return err
+		}
+		traceWriter = f
+	}
+
+	cetChainApp := newApp(ctx.Logger, db, traceWriter).(*app.CetChainApp)
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(cfg.NodeKeyFile())
+	if err != nil {
+		return err
+	}
+	server.UpgradeOldPrivValFile(cfg)
+
+	tmNode, err := node.NewNode(
+		cfg,
+		pvm.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.NewLocalClientCreator(cetChainApp),
+		node.DefaultGenesisDocProviderFunc(cfg),
+		node.DefaultDBProvider,
+		node.DefaultMetricsProvider(cfg.Instrumentation),
+		ctx.Logger.With("module", "node"),
+	)
+	if err != nil {
+		return err
+	}
+	if err := tmNode.Start(); err != nil {
+		return err
+	}
+
+	var cpuProfileCleanup func()
+	if cpuProfile := viper.GetString(flagCPUProfile); cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		ctx.Logger.Info("starting CPU profiler", "profile", cpuProfile)
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		cpuProfileCleanup = func() {
+			ctx.Logger.Info("stopping CPU profiler", "profile", cpuProfile)
+			pprof.StopCPUProfile()
+			f.Close()
+		}
+	}
+
+	server.TrapSignal(func() {
+		if tmNode.IsRunning() {
+			_ = tmNode.Stop()
+		}
+		// no more blocks are committed after the node is stopped
+		cetChainApp.Close()
+
+		if cpuProfileCleanup != nil {
+			cpuProfileCleanup()
+		}
+		ctx.Logger.Info("exiting...")
+	})
+
+	// run forever (the node will not be returned)
+	select {}
+}
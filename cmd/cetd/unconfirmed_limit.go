@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/coinexchain/dex/app"
+)
+
+func unconfirmedLimitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unconfirmed-limit",
+		Short: "Show or update the limit on unconfirmed txs of each account in the running node",
+	}
+	cmd.AddCommand(
+		unconfirmedLimitStatusCmd(),
+		unconfirmedLimitSetCmd(),
+	)
+	return cmd
+}
+
+// addUnconfirmedLimitStartFlags adds the flags for limiting unconfirmed txs to the start command
+func addUnconfirmedLimitStartFlags(rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() != "start" {
+			continue
+		}
+		addUnconfirmedLimitFlags(cmd, app.DefaultUnconfirmedLimitConfig())
+	}
+}
+
+func addUnconfirmedLimitFlags(cmd *cobra.Command, cfg app.UnconfirmedLimitConfig) {
+	cmd.Flags().Int64(app.FlagUnconfirmedTxLimitTime, cfg.LimitTime,
+		"Seconds before an unconfirmed tx stops blocking the other txs of its signers, non-positive to disable the limit")
+	cmd.Flags().Int64(app.FlagUnconfirmedTxMaxExpired, cfg.MaxExpiredPerCommit,
+		"Expired unconfirmed txs removed from the memory in a commit at most")
+	cmd.Flags().Int(app.FlagUnconfirmedTxQuota, cfg.Quota,
+		"Unconfirmed txs allowed for an account, 0 for unlimited")
+	cmd.Flags().StringSlice(app.FlagUnconfirmedTxQuotaOverrides, cfg.QuotaOverrides,
+		"Per-account quotas like addr1:5,addr2:0, which override the default one")
+	cmd.Flags().StringSlice(app.FlagUnconfirmedTxFeeTiers, cfg.FeeTiers,
+		"Quotas like 10:2,100:0 for the txs paying at least 10 or 100 times the min gas price, which raise the account's quota")
+}
+
+func unconfirmedLimitStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the current config, and the one to take effect after the next commit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var status app.UnconfirmedLimitStatus
+			if err := newAdminClient().Get(app.RouteUnconfirmedLimit, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+}
+
+func unconfirmedLimitSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Update the config, which takes effect after the next commit",
+		Long: `Update the config, which takes effect after the next commit.
+Only the values given by flags are changed, for example:
+
+$ cetd unconfirmed-limit set --unconfirmed-tx-quota=2
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newAdminClient()
+			var status app.UnconfirmedLimitStatus
+			if err := client.Get(app.RouteUnconfirmedLimit, &status); err != nil {
+				return err
+			}
+			cfg := status.Current
+			if status.Pending != nil {
+				cfg = *status.Pending
+			}
+			if err := updateUnconfirmedLimitConfig(cmd, &cfg); err != nil {
+				return err
+			}
+			if err := client.Post(app.RouteUnconfirmedLimit, cfg, &status); err != nil {
+				return err
+			}
+			return printJSON(status)
+		},
+	}
+	addUnconfirmedLimitFlags(cmd, app.UnconfirmedLimitConfig{})
+	return cmd
+}
+
+func updateUnconfirmedLimitConfig(cmd *cobra.Command, cfg *app.UnconfirmedLimitConfig) (err error) {
+	flags := cmd.Flags()
+	if !flags.Changed(app.FlagUnconfirmedTxLimitTime) && !flags.Changed(app.FlagUnconfirmedTxMaxExpired) &&
+		!flags.Changed(app.FlagUnconfirmedTxQuota) && !flags.Changed(app.FlagUnconfirmedTxQuotaOverrides) &&
+		!flags.Changed(app.FlagUnconfirmedTxFeeTiers) {
+		return fmt.Errorf("nothing to set")
+	}
+	if flags.Changed(app.FlagUnconfirmedTxLimitTime) {
+		if cfg.LimitTime, err = flags.GetInt64(app.FlagUnconfirmedTxLimitTime); err != nil {
+			return
+		}
+	}
+	if flags.Changed(app.FlagUnconfirmedTxMaxExpired) {
+		if cfg.MaxExpiredPerCommit, err = flags.GetInt64(app.FlagUnconfirmedTxMaxExpired); err != nil {
+			return
+		}
+	}
+	if flags.Changed(app.FlagUnconfirmedTxQuota) {
+		if cfg.Quota, err = flags.GetInt(app.FlagUnconfirmedTxQuota); err != nil {
+			return
+		}
+	}
+	if flags.Changed(app.FlagUnconfirmedTxQuotaOverrides) {
+		if cfg.QuotaOverrides, err = flags.GetStringSlice(app.FlagUnconfirmedTxQuotaOverrides); err != nil {
+			return
+		}
+	}
+	if flags.Changed(app.FlagUnconfirmedTxFeeTiers) {
+		if cfg.FeeTiers, err = flags.GetStringSlice(app.FlagUnconfirmedTxFeeTiers); err != nil {
+			return
+		}
+	}
+	return cfg.Validate()
+}
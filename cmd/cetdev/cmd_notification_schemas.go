@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/coinexchain/dex/app"
+)
+
+const flagSchemaOutputDir = "output-dir"
+
+func NotificationSchemasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notification-schemas [key]",
+		Short: "Print the JSON Schemas of all the versions of the notifications",
+		Long: `Print the JSON Schemas of all the versions of the notifications, or the ones of the given key.
+The schemas are printed as {"<key>": {"v<version>": <schema>}}, or written into files named
+<key>.v<version>.json with --output-dir.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := ""
+			if len(args) != 0 {
+				key = args[0]
+			}
+			return printNotificationSchemas(key, viper.GetString(flagSchemaOutputDir))
+		},
+	}
+	cmd.Flags().String(flagSchemaOutputDir, "", "The dir to write the schema files into")
+	return cmd
+}
+
+func printNotificationSchemas(key, outputDir string) error {
+	all := make(map[string]map[string]interface{})
+	for _, s := range app.NotificationSchemas() {
+		if key != "" && s.Key != key {
+			continue
+		}
+		if all[s.Key] == nil {
+			all[s.Key] = make(map[string]interface{})
+		}
+		all[s.Key][fmt.Sprintf("v%d", s.Version)] = s.JSONSchema()
+	}
+	if len(all) == 0 {
+		return fmt.Errorf("unknown notification key: %s", key)
+	}
+
+	if outputDir == "" {
+		bz, err := json.MarshalIndent(all, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bz))
+		return nil
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+	for k, versions := range all {
+		for v, schema := range versions {
+			bz, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err = ioutil.WriteFile(filepath.Join(outputDir, k+"."+v+".json"), bz, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
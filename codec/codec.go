@@ -7326,6 +7326,2310 @@ func RandMsgAliasUpdate(r RandSrc) MsgAliasUpdate {
 	return v
 } //End of RandMsgAliasUpdate
 
+// Non-Interface
+func EncodeNewHeightInfo(w io.Writer, v NewHeightInfo) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ChainID)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Height))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.TimeStamp))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeByteSlice(w, v.LastBlockHash[:])
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNewHeightInfo
+
+func DecodeNewHeightInfo(bz []byte) (NewHeightInfo, int, error) {
+	// codon version: 1
+	var err error
+	var length int
+	var v NewHeightInfo
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ChainID = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Height = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TimeStamp = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.LastBlockHash, n, err = codonGetByteSlice(bz, length)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNewHeightInfo
+
+func RandNewHeightInfo(r RandSrc) NewHeightInfo {
+	// codon version: 1
+	var length int
+	var v NewHeightInfo
+	v.Version = r.GetInt()
+	v.ChainID = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Height = r.GetInt64()
+	v.TimeStamp = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.LastBlockHash = r.GetBytes(length)
+	return v
+} //End of RandNewHeightInfo
+
+// Non-Interface
+func EncodeTransferRecord(w io.Writer, v TransferRecord) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.Sender)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Recipient)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeTransferRecord
+
+func DecodeTransferRecord(bz []byte) (TransferRecord, int, error) {
+	// codon version: 1
+	var err error
+	var v TransferRecord
+	var n int
+	var total int
+	v.Sender = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Recipient = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeTransferRecord
+
+func RandTransferRecord(r RandSrc) TransferRecord {
+	// codon version: 1
+	var v TransferRecord
+	v.Sender = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Recipient = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandTransferRecord
+
+// Non-Interface
+func EncodeNotificationTx(w io.Writer, v NotificationTx) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Signers)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Signers); _0++ {
+		err = codonEncodeByteSlice(w, v.Signers[_0][:])
+		if err != nil {
+			return err
+		}
+	}
+	err = codonEncodeVarint(w, int64(len(v.Transfers)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Transfers); _0++ {
+		err = codonEncodeString(w, v.Transfers[_0].Sender)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Transfers[_0].Recipient)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Transfers[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Transfers[_0]
+	}
+	err = codonEncodeVarint(w, int64(v.SerialNumber))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.MsgTypes)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.MsgTypes); _0++ {
+		err = codonEncodeString(w, v.MsgTypes[_0])
+		if err != nil {
+			return err
+		}
+	}
+	err = codonEncodeString(w, v.TxJSON)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeByteSlice(w, v.Tx[:])
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Height))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeByteSlice(w, v.Hash[:])
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ExtraInfo)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Fee.Payer)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Fee.Amount)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Fee.Amount); _0++ {
+		err = codonEncodeString(w, v.Fee.Amount[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Fee.Amount[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Fee.Amount[_0]
+	}
+	err = codonEncodeBool(w, v.Fee.Charged)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.Fee.GasLimit))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Fee.GasUsed))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Fee.EffectiveGasPrice)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Fee.EffectiveGasPrice); _0++ {
+		err = codonEncodeString(w, v.Fee.EffectiveGasPrice[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Fee.EffectiveGasPrice[_0].Price)
+		if err != nil {
+			return err
+		}
+		// end of v.Fee.EffectiveGasPrice[_0]
+	}
+	err = codonEncodeVarint(w, int64(len(v.Fee.ToFeeCollector)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Fee.ToFeeCollector); _0++ {
+		err = codonEncodeString(w, v.Fee.ToFeeCollector[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Fee.ToFeeCollector[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Fee.ToFeeCollector[_0]
+	}
+	err = codonEncodeVarint(w, int64(len(v.Fee.ToCommunityPool)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Fee.ToCommunityPool); _0++ {
+		err = codonEncodeString(w, v.Fee.ToCommunityPool[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Fee.ToCommunityPool[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Fee.ToCommunityPool[_0]
+	}
+	// end of v.Fee
+	return nil
+} //End of EncodeNotificationTx
+
+func DecodeNotificationTx(bz []byte) (NotificationTx, int, error) {
+	// codon version: 1
+	var err error
+	var length int
+	var v NotificationTx
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Signers = make([]AccAddress, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of slice
+		length = codonDecodeInt(bz, &n, &err)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+		v.Signers[_0], n, err = codonGetByteSlice(bz, length)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Transfers = make([]TransferRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Transfers[_0], n, err = DecodeTransferRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	v.SerialNumber = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.MsgTypes = make([]string, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of string
+		v.MsgTypes[_0] = string(codonDecodeString(bz, &n, &err))
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	v.TxJSON = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Tx, n, err = codonGetByteSlice(bz, length)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Height = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Hash, n, err = codonGetByteSlice(bz, length)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ExtraInfo = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.Payer = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.Amount = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.Amount[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	v.Fee.Charged = bool(codonDecodeBool(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.GasLimit = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.GasUsed = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.EffectiveGasPrice = make([]GasPriceRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.EffectiveGasPrice[_0], n, err = DecodeGasPriceRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.ToFeeCollector = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.ToFeeCollector[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fee.ToCommunityPool = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.ToCommunityPool[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	// end of v.Fee
+	return v, total, nil
+} //End of DecodeNotificationTx
+
+func RandNotificationTx(r RandSrc) NotificationTx {
+	// codon version: 1
+	var length int
+	var v NotificationTx
+	v.Version = r.GetInt()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Signers = make([]AccAddress, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of slice
+		length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+		v.Signers[_0] = r.GetBytes(length)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Transfers = make([]TransferRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Transfers[_0] = RandTransferRecord(r)
+	}
+	v.SerialNumber = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.MsgTypes = make([]string, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of string
+		v.MsgTypes[_0] = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	}
+	v.TxJSON = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Tx = r.GetBytes(length)
+	v.Height = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Hash = r.GetBytes(length)
+	v.ExtraInfo = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Fee.Payer = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Fee.Amount = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.Amount[_0] = RandFeeRecord(r)
+	}
+	v.Fee.Charged = r.GetBool()
+	v.Fee.GasLimit = r.GetUint64()
+	v.Fee.GasUsed = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Fee.EffectiveGasPrice = make([]GasPriceRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.EffectiveGasPrice[_0] = RandGasPriceRecord(r)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Fee.ToFeeCollector = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.ToFeeCollector[_0] = RandFeeRecord(r)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Fee.ToCommunityPool = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fee.ToCommunityPool[_0] = RandFeeRecord(r)
+	}
+	// end of v.Fee
+	return v
+} //End of RandNotificationTx
+
+// Non-Interface
+func EncodeNotificationBeginRedelegation(w io.Writer, v NotificationBeginRedelegation) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Delegator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ValidatorSrc)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ValidatorDst)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.CompletionTime))
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationBeginRedelegation
+
+func DecodeNotificationBeginRedelegation(bz []byte) (NotificationBeginRedelegation, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationBeginRedelegation
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Delegator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ValidatorSrc = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ValidatorDst = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.CompletionTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationBeginRedelegation
+
+func RandNotificationBeginRedelegation(r RandSrc) NotificationBeginRedelegation {
+	// codon version: 1
+	var v NotificationBeginRedelegation
+	v.Version = r.GetInt()
+	v.Delegator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.ValidatorSrc = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.ValidatorDst = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.CompletionTime = r.GetInt64()
+	return v
+} //End of RandNotificationBeginRedelegation
+
+// Non-Interface
+func EncodeNotificationBeginUnbonding(w io.Writer, v NotificationBeginUnbonding) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Delegator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Validator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.CompletionTime))
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationBeginUnbonding
+
+func DecodeNotificationBeginUnbonding(bz []byte) (NotificationBeginUnbonding, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationBeginUnbonding
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Delegator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Validator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.CompletionTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationBeginUnbonding
+
+func RandNotificationBeginUnbonding(r RandSrc) NotificationBeginUnbonding {
+	// codon version: 1
+	var v NotificationBeginUnbonding
+	v.Version = r.GetInt()
+	v.Delegator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Validator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.CompletionTime = r.GetInt64()
+	return v
+} //End of RandNotificationBeginUnbonding
+
+// Non-Interface
+func EncodeNotificationCompleteRedelegation(w io.Writer, v NotificationCompleteRedelegation) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Delegator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ValidatorSrc)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ValidatorDst)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationCompleteRedelegation
+
+func DecodeNotificationCompleteRedelegation(bz []byte) (NotificationCompleteRedelegation, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationCompleteRedelegation
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Delegator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ValidatorSrc = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ValidatorDst = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationCompleteRedelegation
+
+func RandNotificationCompleteRedelegation(r RandSrc) NotificationCompleteRedelegation {
+	// codon version: 1
+	var v NotificationCompleteRedelegation
+	v.Version = r.GetInt()
+	v.Delegator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.ValidatorSrc = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.ValidatorDst = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationCompleteRedelegation
+
+// Non-Interface
+func EncodeNotificationCompleteUnbonding(w io.Writer, v NotificationCompleteUnbonding) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Delegator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Validator)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationCompleteUnbonding
+
+func DecodeNotificationCompleteUnbonding(bz []byte) (NotificationCompleteUnbonding, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationCompleteUnbonding
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Delegator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Validator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationCompleteUnbonding
+
+func RandNotificationCompleteUnbonding(r RandSrc) NotificationCompleteUnbonding {
+	// codon version: 1
+	var v NotificationCompleteUnbonding
+	v.Version = r.GetInt()
+	v.Delegator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Validator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationCompleteUnbonding
+
+// Non-Interface
+func EncodeNotificationSlash(w io.Writer, v NotificationSlash) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Validator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Power)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Reason)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeBool(w, v.Jailed)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationSlash
+
+func DecodeNotificationSlash(bz []byte) (NotificationSlash, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationSlash
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Validator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Power = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Reason = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Jailed = bool(codonDecodeBool(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationSlash
+
+func RandNotificationSlash(r RandSrc) NotificationSlash {
+	// codon version: 1
+	var v NotificationSlash
+	v.Version = r.GetInt()
+	v.Validator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Power = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Reason = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Jailed = r.GetBool()
+	return v
+} //End of RandNotificationSlash
+
+// Non-Interface
+func EncodeNotificationValidatorCommission(w io.Writer, v NotificationValidatorCommission) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Validator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Commission)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationValidatorCommission
+
+func DecodeNotificationValidatorCommission(bz []byte) (NotificationValidatorCommission, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationValidatorCommission
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Validator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Commission = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationValidatorCommission
+
+func RandNotificationValidatorCommission(r RandSrc) NotificationValidatorCommission {
+	// codon version: 1
+	var v NotificationValidatorCommission
+	v.Version = r.GetInt()
+	v.Validator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Commission = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationValidatorCommission
+
+// Non-Interface
+func EncodeNotificationDelegatorRewards(w io.Writer, v NotificationDelegatorRewards) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Validator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Rewards)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationDelegatorRewards
+
+func DecodeNotificationDelegatorRewards(bz []byte) (NotificationDelegatorRewards, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationDelegatorRewards
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Validator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Rewards = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationDelegatorRewards
+
+func RandNotificationDelegatorRewards(r RandSrc) NotificationDelegatorRewards {
+	// codon version: 1
+	var v NotificationDelegatorRewards
+	v.Version = r.GetInt()
+	v.Validator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Rewards = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationDelegatorRewards
+
+// Non-Interface
+func EncodeValidatorUpdateRecord(w io.Writer, v ValidatorUpdateRecord) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.ConsensusPubKey)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Operator)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Power))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.DropReason)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeValidatorUpdateRecord
+
+func DecodeValidatorUpdateRecord(bz []byte) (ValidatorUpdateRecord, int, error) {
+	// codon version: 1
+	var err error
+	var v ValidatorUpdateRecord
+	var n int
+	var total int
+	v.ConsensusPubKey = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Operator = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Power = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.DropReason = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeValidatorUpdateRecord
+
+func RandValidatorUpdateRecord(r RandSrc) ValidatorUpdateRecord {
+	// codon version: 1
+	var v ValidatorUpdateRecord
+	v.ConsensusPubKey = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Operator = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Power = r.GetInt64()
+	v.DropReason = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandValidatorUpdateRecord
+
+// Non-Interface
+func EncodeNotificationValidatorSetUpdate(w io.Writer, v NotificationValidatorSetUpdate) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Updates)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Updates); _0++ {
+		err = codonEncodeString(w, v.Updates[_0].ConsensusPubKey)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Updates[_0].Operator)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeVarint(w, int64(v.Updates[_0].Power))
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Updates[_0].DropReason)
+		if err != nil {
+			return err
+		}
+		// end of v.Updates[_0]
+	}
+	return nil
+} //End of EncodeNotificationValidatorSetUpdate
+
+func DecodeNotificationValidatorSetUpdate(bz []byte) (NotificationValidatorSetUpdate, int, error) {
+	// codon version: 1
+	var err error
+	var length int
+	var v NotificationValidatorSetUpdate
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Updates = make([]ValidatorUpdateRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Updates[_0], n, err = DecodeValidatorUpdateRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	return v, total, nil
+} //End of DecodeNotificationValidatorSetUpdate
+
+func RandNotificationValidatorSetUpdate(r RandSrc) NotificationValidatorSetUpdate {
+	// codon version: 1
+	var length int
+	var v NotificationValidatorSetUpdate
+	v.Version = r.GetInt()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Updates = make([]ValidatorUpdateRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Updates[_0] = RandValidatorUpdateRecord(r)
+	}
+	return v
+} //End of RandNotificationValidatorSetUpdate
+
+// Non-Interface
+func EncodeNotificationSubmitProposal(w io.Writer, v NotificationSubmitProposal) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.ProposalID))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Proposer)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.ProposalType)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Title)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Description)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.SubmitTime))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.DepositEndTime))
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationSubmitProposal
+
+func DecodeNotificationSubmitProposal(bz []byte) (NotificationSubmitProposal, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationSubmitProposal
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalID = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Proposer = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalType = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Title = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Description = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.SubmitTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.DepositEndTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationSubmitProposal
+
+func RandNotificationSubmitProposal(r RandSrc) NotificationSubmitProposal {
+	// codon version: 1
+	var v NotificationSubmitProposal
+	v.Version = r.GetInt()
+	v.ProposalID = r.GetUint64()
+	v.Proposer = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.ProposalType = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Title = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Description = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.SubmitTime = r.GetInt64()
+	v.DepositEndTime = r.GetInt64()
+	return v
+} //End of RandNotificationSubmitProposal
+
+// Non-Interface
+func EncodeNotificationProposalDeposit(w io.Writer, v NotificationProposalDeposit) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.ProposalID))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Depositor)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationProposalDeposit
+
+func DecodeNotificationProposalDeposit(bz []byte) (NotificationProposalDeposit, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationProposalDeposit
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalID = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Depositor = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationProposalDeposit
+
+func RandNotificationProposalDeposit(r RandSrc) NotificationProposalDeposit {
+	// codon version: 1
+	var v NotificationProposalDeposit
+	v.Version = r.GetInt()
+	v.ProposalID = r.GetUint64()
+	v.Depositor = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationProposalDeposit
+
+// Non-Interface
+func EncodeNotificationProposalVote(w io.Writer, v NotificationProposalVote) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.ProposalID))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Voter)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Option)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationProposalVote
+
+func DecodeNotificationProposalVote(bz []byte) (NotificationProposalVote, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationProposalVote
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalID = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Voter = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Option = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationProposalVote
+
+func RandNotificationProposalVote(r RandSrc) NotificationProposalVote {
+	// codon version: 1
+	var v NotificationProposalVote
+	v.Version = r.GetInt()
+	v.ProposalID = r.GetUint64()
+	v.Voter = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Option = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationProposalVote
+
+// Non-Interface
+func EncodeNotificationVotingPeriodStart(w io.Writer, v NotificationVotingPeriodStart) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.ProposalID))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.TotalDeposit)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.VotingStartTime))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.VotingEndTime))
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationVotingPeriodStart
+
+func DecodeNotificationVotingPeriodStart(bz []byte) (NotificationVotingPeriodStart, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationVotingPeriodStart
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalID = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TotalDeposit = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.VotingStartTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.VotingEndTime = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationVotingPeriodStart
+
+func RandNotificationVotingPeriodStart(r RandSrc) NotificationVotingPeriodStart {
+	// codon version: 1
+	var v NotificationVotingPeriodStart
+	v.Version = r.GetInt()
+	v.ProposalID = r.GetUint64()
+	v.TotalDeposit = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.VotingStartTime = r.GetInt64()
+	v.VotingEndTime = r.GetInt64()
+	return v
+} //End of RandNotificationVotingPeriodStart
+
+// Non-Interface
+func EncodeTallyResultRecord(w io.Writer, v TallyResultRecord) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.Yes)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Abstain)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.No)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.NoWithVeto)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeTallyResultRecord
+
+func DecodeTallyResultRecord(bz []byte) (TallyResultRecord, int, error) {
+	// codon version: 1
+	var err error
+	var v TallyResultRecord
+	var n int
+	var total int
+	v.Yes = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Abstain = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.No = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.NoWithVeto = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeTallyResultRecord
+
+func RandTallyResultRecord(r RandSrc) TallyResultRecord {
+	// codon version: 1
+	var v TallyResultRecord
+	v.Yes = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Abstain = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.No = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.NoWithVeto = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandTallyResultRecord
+
+// Non-Interface
+func EncodeNotificationProposalResult(w io.Writer, v NotificationProposalResult) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.ProposalID))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Result)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeBool(w, v.HasTallyResult)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.TallyResult.Yes)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.TallyResult.Abstain)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.TallyResult.No)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.TallyResult.NoWithVeto)
+	if err != nil {
+		return err
+	}
+	// end of v.TallyResult
+	err = codonEncodeString(w, v.TotalDeposit)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationProposalResult
+
+func DecodeNotificationProposalResult(bz []byte) (NotificationProposalResult, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationProposalResult
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ProposalID = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Result = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.HasTallyResult = bool(codonDecodeBool(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TallyResult.Yes = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TallyResult.Abstain = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TallyResult.No = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TallyResult.NoWithVeto = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	// end of v.TallyResult
+	v.TotalDeposit = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationProposalResult
+
+func RandNotificationProposalResult(r RandSrc) NotificationProposalResult {
+	// codon version: 1
+	var v NotificationProposalResult
+	v.Version = r.GetInt()
+	v.ProposalID = r.GetUint64()
+	v.Result = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.HasTallyResult = r.GetBool()
+	v.TallyResult.Yes = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.TallyResult.Abstain = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.TallyResult.No = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.TallyResult.NoWithVeto = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	// end of v.TallyResult
+	v.TotalDeposit = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationProposalResult
+
+// Non-Interface
+func EncodeNotificationUnlock(w io.Writer, v NotificationUnlock) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Address)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Denom)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Spendable)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Source)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeNotificationUnlock
+
+func DecodeNotificationUnlock(bz []byte) (NotificationUnlock, int, error) {
+	// codon version: 1
+	var err error
+	var v NotificationUnlock
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Address = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Denom = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Spendable = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Source = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeNotificationUnlock
+
+func RandNotificationUnlock(r RandSrc) NotificationUnlock {
+	// codon version: 1
+	var v NotificationUnlock
+	v.Version = r.GetInt()
+	v.Address = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Denom = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Spendable = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Source = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandNotificationUnlock
+
+// Non-Interface
+func EncodeMsgTypeCount(w io.Writer, v MsgTypeCount) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.MsgType)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Count))
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeMsgTypeCount
+
+func DecodeMsgTypeCount(bz []byte) (MsgTypeCount, int, error) {
+	// codon version: 1
+	var err error
+	var v MsgTypeCount
+	var n int
+	var total int
+	v.MsgType = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Count = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeMsgTypeCount
+
+func RandMsgTypeCount(r RandSrc) MsgTypeCount {
+	// codon version: 1
+	var v MsgTypeCount
+	v.MsgType = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Count = r.GetInt()
+	return v
+} //End of RandMsgTypeCount
+
+// Non-Interface
+func EncodeFeeRecord(w io.Writer, v FeeRecord) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.Denom)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Amount)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeFeeRecord
+
+func DecodeFeeRecord(bz []byte) (FeeRecord, int, error) {
+	// codon version: 1
+	var err error
+	var v FeeRecord
+	var n int
+	var total int
+	v.Denom = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeFeeRecord
+
+func RandFeeRecord(r RandSrc) FeeRecord {
+	// codon version: 1
+	var v FeeRecord
+	v.Denom = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Amount = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandFeeRecord
+
+// Non-Interface
+func EncodeNotificationBlockSummary(w io.Writer, v NotificationBlockSummary) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeVarint(w, int64(v.Version))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.Height))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.TxCount))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.SuccessCount))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.FailureCount))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.MsgTypeCounts)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.MsgTypeCounts); _0++ {
+		err = codonEncodeString(w, v.MsgTypeCounts[_0].MsgType)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeVarint(w, int64(v.MsgTypeCounts[_0].Count))
+		if err != nil {
+			return err
+		}
+		// end of v.MsgTypeCounts[_0]
+	}
+	err = codonEncodeVarint(w, int64(v.GasWanted))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.GasUsed))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Fees)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Fees); _0++ {
+		err = codonEncodeString(w, v.Fees[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Fees[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Fees[_0]
+	}
+	err = codonEncodeVarint(w, int64(len(v.MsgQueueModules)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.MsgQueueModules); _0++ {
+		err = codonEncodeString(w, v.MsgQueueModules[_0])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+} //End of EncodeNotificationBlockSummary
+
+func DecodeNotificationBlockSummary(bz []byte) (NotificationBlockSummary, int, error) {
+	// codon version: 1
+	var err error
+	var length int
+	var v NotificationBlockSummary
+	var n int
+	var total int
+	v.Version = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Height = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.TxCount = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.SuccessCount = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.FailureCount = int(codonDecodeInt(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.MsgTypeCounts = make([]MsgTypeCount, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.MsgTypeCounts[_0], n, err = DecodeMsgTypeCount(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	v.GasWanted = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.GasUsed = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Fees = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fees[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.MsgQueueModules = make([]string, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of string
+		v.MsgQueueModules[_0] = string(codonDecodeString(bz, &n, &err))
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	return v, total, nil
+} //End of DecodeNotificationBlockSummary
+
+func RandNotificationBlockSummary(r RandSrc) NotificationBlockSummary {
+	// codon version: 1
+	var length int
+	var v NotificationBlockSummary
+	v.Version = r.GetInt()
+	v.Height = r.GetInt64()
+	v.TxCount = r.GetInt()
+	v.SuccessCount = r.GetInt()
+	v.FailureCount = r.GetInt()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.MsgTypeCounts = make([]MsgTypeCount, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.MsgTypeCounts[_0] = RandMsgTypeCount(r)
+	}
+	v.GasWanted = r.GetInt64()
+	v.GasUsed = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Fees = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Fees[_0] = RandFeeRecord(r)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.MsgQueueModules = make([]string, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of string
+		v.MsgQueueModules[_0] = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	}
+	return v
+} //End of RandNotificationBlockSummary
+
+// Non-Interface
+func EncodeGasPriceRecord(w io.Writer, v GasPriceRecord) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.Denom)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeString(w, v.Price)
+	if err != nil {
+		return err
+	}
+	return nil
+} //End of EncodeGasPriceRecord
+
+func DecodeGasPriceRecord(bz []byte) (GasPriceRecord, int, error) {
+	// codon version: 1
+	var err error
+	var v GasPriceRecord
+	var n int
+	var total int
+	v.Denom = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Price = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	return v, total, nil
+} //End of DecodeGasPriceRecord
+
+func RandGasPriceRecord(r RandSrc) GasPriceRecord {
+	// codon version: 1
+	var v GasPriceRecord
+	v.Denom = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	v.Price = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	return v
+} //End of RandGasPriceRecord
+
+// Non-Interface
+func EncodeTxFeeInfo(w io.Writer, v TxFeeInfo) error {
+	// codon version: 1
+	var err error
+	err = codonEncodeString(w, v.Payer)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.Amount)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.Amount); _0++ {
+		err = codonEncodeString(w, v.Amount[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.Amount[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.Amount[_0]
+	}
+	err = codonEncodeBool(w, v.Charged)
+	if err != nil {
+		return err
+	}
+	err = codonEncodeUvarint(w, uint64(v.GasLimit))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(v.GasUsed))
+	if err != nil {
+		return err
+	}
+	err = codonEncodeVarint(w, int64(len(v.EffectiveGasPrice)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.EffectiveGasPrice); _0++ {
+		err = codonEncodeString(w, v.EffectiveGasPrice[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.EffectiveGasPrice[_0].Price)
+		if err != nil {
+			return err
+		}
+		// end of v.EffectiveGasPrice[_0]
+	}
+	err = codonEncodeVarint(w, int64(len(v.ToFeeCollector)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.ToFeeCollector); _0++ {
+		err = codonEncodeString(w, v.ToFeeCollector[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.ToFeeCollector[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.ToFeeCollector[_0]
+	}
+	err = codonEncodeVarint(w, int64(len(v.ToCommunityPool)))
+	if err != nil {
+		return err
+	}
+	for _0 := 0; _0 < len(v.ToCommunityPool); _0++ {
+		err = codonEncodeString(w, v.ToCommunityPool[_0].Denom)
+		if err != nil {
+			return err
+		}
+		err = codonEncodeString(w, v.ToCommunityPool[_0].Amount)
+		if err != nil {
+			return err
+		}
+		// end of v.ToCommunityPool[_0]
+	}
+	return nil
+} //End of EncodeTxFeeInfo
+
+func DecodeTxFeeInfo(bz []byte) (TxFeeInfo, int, error) {
+	// codon version: 1
+	var err error
+	var length int
+	var v TxFeeInfo
+	var n int
+	var total int
+	v.Payer = string(codonDecodeString(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.Amount = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Amount[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	v.Charged = bool(codonDecodeBool(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.GasLimit = uint64(codonDecodeUint64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.GasUsed = int64(codonDecodeInt64(bz, &n, &err))
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.EffectiveGasPrice = make([]GasPriceRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.EffectiveGasPrice[_0], n, err = DecodeGasPriceRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ToFeeCollector = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.ToFeeCollector[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	length = codonDecodeInt(bz, &n, &err)
+	if err != nil {
+		return v, total, err
+	}
+	bz = bz[n:]
+	total += n
+	v.ToCommunityPool = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.ToCommunityPool[_0], n, err = DecodeFeeRecord(bz)
+		if err != nil {
+			return v, total, err
+		}
+		bz = bz[n:]
+		total += n
+	}
+	return v, total, nil
+} //End of DecodeTxFeeInfo
+
+func RandTxFeeInfo(r RandSrc) TxFeeInfo {
+	// codon version: 1
+	var length int
+	var v TxFeeInfo
+	v.Payer = r.GetString(1 + int(r.GetUint()%(MaxStringLength-1)))
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.Amount = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.Amount[_0] = RandFeeRecord(r)
+	}
+	v.Charged = r.GetBool()
+	v.GasLimit = r.GetUint64()
+	v.GasUsed = r.GetInt64()
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.EffectiveGasPrice = make([]GasPriceRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.EffectiveGasPrice[_0] = RandGasPriceRecord(r)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.ToFeeCollector = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.ToFeeCollector[_0] = RandFeeRecord(r)
+	}
+	length = 1 + int(r.GetUint()%(MaxSliceLength-1))
+	v.ToCommunityPool = make([]FeeRecord, length)
+	for _0, length_0 := 0, length; _0 < length_0; _0++ { //slice of struct
+		v.ToCommunityPool[_0] = RandFeeRecord(r)
+	}
+	return v
+} //End of RandTxFeeInfo
+
 // Interface
 func EncodePubKey(w io.Writer, x interface{}) error {
 	switch v := x.(type) {
@@ -8022,6 +10326,10 @@ func getMagicBytes(name string) []byte {
 		return []byte{187, 71, 224, 1}
 	case "DuplicateVoteEvidence":
 		return []byte{130, 76, 198, 17}
+	case "FeeRecord":
+		return []byte{87, 242, 73, 118}
+	case "GasPriceRecord":
+		return []byte{168, 214, 188, 50}
 	case "Input":
 		return []byte{165, 152, 189, 47}
 	case "LockedCoin":
@@ -8094,6 +10402,8 @@ func getMagicBytes(name string) []byte {
 		return []byte{115, 119, 137, 48}
 	case "MsgTransferOwnership":
 		return []byte{200, 224, 118, 175}
+	case "MsgTypeCount":
+		return []byte{221, 229, 131, 96}
 	case "MsgUnForbidAddr":
 		return []byte{167, 165, 166, 227}
 	case "MsgUnForbidToken":
@@ -8110,6 +10420,40 @@ func getMagicBytes(name string) []byte {
 		return []byte{94, 251, 176, 152}
 	case "MsgWithdrawValidatorCommission":
 		return []byte{18, 172, 190, 152}
+	case "NewHeightInfo":
+		return []byte{83, 224, 250, 93}
+	case "NotificationBeginRedelegation":
+		return []byte{254, 170, 237, 194}
+	case "NotificationBeginUnbonding":
+		return []byte{160, 47, 248, 154}
+	case "NotificationBlockSummary":
+		return []byte{45, 90, 181, 233}
+	case "NotificationCompleteRedelegation":
+		return []byte{160, 81, 61, 92}
+	case "NotificationCompleteUnbonding":
+		return []byte{63, 71, 143, 54}
+	case "NotificationDelegatorRewards":
+		return []byte{79, 46, 192, 231}
+	case "NotificationProposalDeposit":
+		return []byte{44, 144, 21, 215}
+	case "NotificationProposalResult":
+		return []byte{216, 63, 18, 223}
+	case "NotificationProposalVote":
+		return []byte{158, 20, 156, 188}
+	case "NotificationSlash":
+		return []byte{20, 221, 69, 149}
+	case "NotificationSubmitProposal":
+		return []byte{135, 88, 76, 14}
+	case "NotificationTx":
+		return []byte{233, 19, 77, 125}
+	case "NotificationUnlock":
+		return []byte{201, 190, 59, 20}
+	case "NotificationValidatorCommission":
+		return []byte{192, 105, 82, 175}
+	case "NotificationValidatorSetUpdate":
+		return []byte{246, 192, 35, 1}
+	case "NotificationVotingPeriodStart":
+		return []byte{82, 218, 8, 164}
 	case "Order":
 		return []byte{57, 115, 63, 27}
 
@@ -8141,8 +10485,16 @@ func getMagicBytes(name string) []byte {
 		return []byte{71, 175, 179, 184}
 	case "Supply":
 		return []byte{233, 209, 209, 86}
+	case "TallyResultRecord":
+		return []byte{20, 74, 254, 112}
 	case "TextProposal":
 		return []byte{169, 32, 176, 245}
+	case "TransferRecord":
+		return []byte{107, 88, 144, 13}
+	case "TxFeeInfo":
+		return []byte{229, 54, 28, 217}
+	case "ValidatorUpdateRecord":
+		return []byte{196, 176, 131, 229}
 	case "Vote":
 		return []byte{113, 227, 24, 224}
 	case "VoteOption":
@@ -8219,6 +10571,18 @@ func EncodeAny(w io.Writer, x interface{}) error {
 	case *DuplicateVoteEvidence:
 		w.Write(getMagicBytes("DuplicateVoteEvidence"))
 		return EncodeDuplicateVoteEvidence(w, *v)
+	case FeeRecord:
+		w.Write(getMagicBytes("FeeRecord"))
+		return EncodeFeeRecord(w, v)
+	case *FeeRecord:
+		w.Write(getMagicBytes("FeeRecord"))
+		return EncodeFeeRecord(w, *v)
+	case GasPriceRecord:
+		w.Write(getMagicBytes("GasPriceRecord"))
+		return EncodeGasPriceRecord(w, v)
+	case *GasPriceRecord:
+		w.Write(getMagicBytes("GasPriceRecord"))
+		return EncodeGasPriceRecord(w, *v)
 	case Input:
 		w.Write(getMagicBytes("Input"))
 		return EncodeInput(w, v)
@@ -8435,6 +10799,12 @@ func EncodeAny(w io.Writer, x interface{}) error {
 	case *MsgTransferOwnership:
 		w.Write(getMagicBytes("MsgTransferOwnership"))
 		return EncodeMsgTransferOwnership(w, *v)
+	case MsgTypeCount:
+		w.Write(getMagicBytes("MsgTypeCount"))
+		return EncodeMsgTypeCount(w, v)
+	case *MsgTypeCount:
+		w.Write(getMagicBytes("MsgTypeCount"))
+		return EncodeMsgTypeCount(w, *v)
 	case MsgUnForbidAddr:
 		w.Write(getMagicBytes("MsgUnForbidAddr"))
 		return EncodeMsgUnForbidAddr(w, v)
@@ -8483,6 +10853,108 @@ func EncodeAny(w io.Writer, x interface{}) error {
 	case *MsgWithdrawValidatorCommission:
 		w.Write(getMagicBytes("MsgWithdrawValidatorCommission"))
 		return EncodeMsgWithdrawValidatorCommission(w, *v)
+	case NewHeightInfo:
+		w.Write(getMagicBytes("NewHeightInfo"))
+		return EncodeNewHeightInfo(w, v)
+	case *NewHeightInfo:
+		w.Write(getMagicBytes("NewHeightInfo"))
+		return EncodeNewHeightInfo(w, *v)
+	case NotificationBeginRedelegation:
+		w.Write(getMagicBytes("NotificationBeginRedelegation"))
+		return EncodeNotificationBeginRedelegation(w, v)
+	case *NotificationBeginRedelegation:
+		w.Write(getMagicBytes("NotificationBeginRedelegation"))
+		return EncodeNotificationBeginRedelegation(w, *v)
+	case NotificationBeginUnbonding:
+		w.Write(getMagicBytes("NotificationBeginUnbonding"))
+		return EncodeNotificationBeginUnbonding(w, v)
+	case *NotificationBeginUnbonding:
+		w.Write(getMagicBytes("NotificationBeginUnbonding"))
+		return EncodeNotificationBeginUnbonding(w, *v)
+	case NotificationBlockSummary:
+		w.Write(getMagicBytes("NotificationBlockSummary"))
+		return EncodeNotificationBlockSummary(w, v)
+	case *NotificationBlockSummary:
+		w.Write(getMagicBytes("NotificationBlockSummary"))
+		return EncodeNotificationBlockSummary(w, *v)
+	case NotificationCompleteRedelegation:
+		w.Write(getMagicBytes("NotificationCompleteRedelegation"))
+		return EncodeNotificationCompleteRedelegation(w, v)
+	case *NotificationCompleteRedelegation:
+		w.Write(getMagicBytes("NotificationCompleteRedelegation"))
+		return EncodeNotificationCompleteRedelegation(w, *v)
+	case NotificationCompleteUnbonding:
+		w.Write(getMagicBytes("NotificationCompleteUnbonding"))
+		return EncodeNotificationCompleteUnbonding(w, v)
+	case *NotificationCompleteUnbonding:
+		w.Write(getMagicBytes("NotificationCompleteUnbonding"))
+		return EncodeNotificationCompleteUnbonding(w, *v)
+	case NotificationDelegatorRewards:
+		w.Write(getMagicBytes("NotificationDelegatorRewards"))
+		return EncodeNotificationDelegatorRewards(w, v)
+	case *NotificationDelegatorRewards:
+		w.Write(getMagicBytes("NotificationDelegatorRewards"))
+		return EncodeNotificationDelegatorRewards(w, *v)
+	case NotificationProposalDeposit:
+		w.Write(getMagicBytes("NotificationProposalDeposit"))
+		return EncodeNotificationProposalDeposit(w, v)
+	case *NotificationProposalDeposit:
+		w.Write(getMagicBytes("NotificationProposalDeposit"))
+		return EncodeNotificationProposalDeposit(w, *v)
+	case NotificationProposalResult:
+		w.Write(getMagicBytes("NotificationProposalResult"))
+		return EncodeNotificationProposalResult(w, v)
+	case *NotificationProposalResult:
+		w.Write(getMagicBytes("NotificationProposalResult"))
+		return EncodeNotificationProposalResult(w, *v)
+	case NotificationProposalVote:
+		w.Write(getMagicBytes("NotificationProposalVote"))
+		return EncodeNotificationProposalVote(w, v)
+	case *NotificationProposalVote:
+		w.Write(getMagicBytes("NotificationProposalVote"))
+		return EncodeNotificationProposalVote(w, *v)
+	case NotificationSlash:
+		w.Write(getMagicBytes("NotificationSlash"))
+		return EncodeNotificationSlash(w, v)
+	case *NotificationSlash:
+		w.Write(getMagicBytes("NotificationSlash"))
+		return EncodeNotificationSlash(w, *v)
+	case NotificationSubmitProposal:
+		w.Write(getMagicBytes("NotificationSubmitProposal"))
+		return EncodeNotificationSubmitProposal(w, v)
+	case *NotificationSubmitProposal:
+		w.Write(getMagicBytes("NotificationSubmitProposal"))
+		return EncodeNotificationSubmitProposal(w, *v)
+	case NotificationTx:
+		w.Write(getMagicBytes("NotificationTx"))
+		return EncodeNotificationTx(w, v)
+	case *NotificationTx:
+		w.Write(getMagicBytes("NotificationTx"))
+		return EncodeNotificationTx(w, *v)
+	case NotificationUnlock:
+		w.Write(getMagicBytes("NotificationUnlock"))
+		return EncodeNotificationUnlock(w, v)
+	case *NotificationUnlock:
+		w.Write(getMagicBytes("NotificationUnlock"))
+		return EncodeNotificationUnlock(w, *v)
+	case NotificationValidatorCommission:
+		w.Write(getMagicBytes("NotificationValidatorCommission"))
+		return EncodeNotificationValidatorCommission(w, v)
+	case *NotificationValidatorCommission:
+		w.Write(getMagicBytes("NotificationValidatorCommission"))
+		return EncodeNotificationValidatorCommission(w, *v)
+	case NotificationValidatorSetUpdate:
+		w.Write(getMagicBytes("NotificationValidatorSetUpdate"))
+		return EncodeNotificationValidatorSetUpdate(w, v)
+	case *NotificationValidatorSetUpdate:
+		w.Write(getMagicBytes("NotificationValidatorSetUpdate"))
+		return EncodeNotificationValidatorSetUpdate(w, *v)
+	case NotificationVotingPeriodStart:
+		w.Write(getMagicBytes("NotificationVotingPeriodStart"))
+		return EncodeNotificationVotingPeriodStart(w, v)
+	case *NotificationVotingPeriodStart:
+		w.Write(getMagicBytes("NotificationVotingPeriodStart"))
+		return EncodeNotificationVotingPeriodStart(w, *v)
 	case Order:
 		w.Write(getMagicBytes("Order"))
 		return EncodeOrder(w, v)
@@ -8573,12 +11045,36 @@ func EncodeAny(w io.Writer, x interface{}) error {
 	case *Supply:
 		w.Write(getMagicBytes("Supply"))
 		return EncodeSupply(w, *v)
+	case TallyResultRecord:
+		w.Write(getMagicBytes("TallyResultRecord"))
+		return EncodeTallyResultRecord(w, v)
+	case *TallyResultRecord:
+		w.Write(getMagicBytes("TallyResultRecord"))
+		return EncodeTallyResultRecord(w, *v)
 	case TextProposal:
 		w.Write(getMagicBytes("TextProposal"))
 		return EncodeTextProposal(w, v)
 	case *TextProposal:
 		w.Write(getMagicBytes("TextProposal"))
 		return EncodeTextProposal(w, *v)
+	case TransferRecord:
+		w.Write(getMagicBytes("TransferRecord"))
+		return EncodeTransferRecord(w, v)
+	case *TransferRecord:
+		w.Write(getMagicBytes("TransferRecord"))
+		return EncodeTransferRecord(w, *v)
+	case TxFeeInfo:
+		w.Write(getMagicBytes("TxFeeInfo"))
+		return EncodeTxFeeInfo(w, v)
+	case *TxFeeInfo:
+		w.Write(getMagicBytes("TxFeeInfo"))
+		return EncodeTxFeeInfo(w, *v)
+	case ValidatorUpdateRecord:
+		w.Write(getMagicBytes("ValidatorUpdateRecord"))
+		return EncodeValidatorUpdateRecord(w, v)
+	case *ValidatorUpdateRecord:
+		w.Write(getMagicBytes("ValidatorUpdateRecord"))
+		return EncodeValidatorUpdateRecord(w, *v)
 	case Vote:
 		w.Write(getMagicBytes("Vote"))
 		return EncodeVote(w, v)
@@ -8641,6 +11137,14 @@ func BareEncodeAny(w io.Writer, x interface{}) error {
 		return EncodeDuplicateVoteEvidence(w, v)
 	case *DuplicateVoteEvidence:
 		return EncodeDuplicateVoteEvidence(w, *v)
+	case FeeRecord:
+		return EncodeFeeRecord(w, v)
+	case *FeeRecord:
+		return EncodeFeeRecord(w, *v)
+	case GasPriceRecord:
+		return EncodeGasPriceRecord(w, v)
+	case *GasPriceRecord:
+		return EncodeGasPriceRecord(w, *v)
 	case Input:
 		return EncodeInput(w, v)
 	case *Input:
@@ -8785,6 +11289,10 @@ func BareEncodeAny(w io.Writer, x interface{}) error {
 		return EncodeMsgTransferOwnership(w, v)
 	case *MsgTransferOwnership:
 		return EncodeMsgTransferOwnership(w, *v)
+	case MsgTypeCount:
+		return EncodeMsgTypeCount(w, v)
+	case *MsgTypeCount:
+		return EncodeMsgTypeCount(w, *v)
 	case MsgUnForbidAddr:
 		return EncodeMsgUnForbidAddr(w, v)
 	case *MsgUnForbidAddr:
@@ -8817,6 +11325,74 @@ func BareEncodeAny(w io.Writer, x interface{}) error {
 		return EncodeMsgWithdrawValidatorCommission(w, v)
 	case *MsgWithdrawValidatorCommission:
 		return EncodeMsgWithdrawValidatorCommission(w, *v)
+	case NewHeightInfo:
+		return EncodeNewHeightInfo(w, v)
+	case *NewHeightInfo:
+		return EncodeNewHeightInfo(w, *v)
+	case NotificationBeginRedelegation:
+		return EncodeNotificationBeginRedelegation(w, v)
+	case *NotificationBeginRedelegation:
+		return EncodeNotificationBeginRedelegation(w, *v)
+	case NotificationBeginUnbonding:
+		return EncodeNotificationBeginUnbonding(w, v)
+	case *NotificationBeginUnbonding:
+		return EncodeNotificationBeginUnbonding(w, *v)
+	case NotificationBlockSummary:
+		return EncodeNotificationBlockSummary(w, v)
+	case *NotificationBlockSummary:
+		return EncodeNotificationBlockSummary(w, *v)
+	case NotificationCompleteRedelegation:
+		return EncodeNotificationCompleteRedelegation(w, v)
+	case *NotificationCompleteRedelegation:
+		return EncodeNotificationCompleteRedelegation(w, *v)
+	case NotificationCompleteUnbonding:
+		return EncodeNotificationCompleteUnbonding(w, v)
+	case *NotificationCompleteUnbonding:
+		return EncodeNotificationCompleteUnbonding(w, *v)
+	case NotificationDelegatorRewards:
+		return EncodeNotificationDelegatorRewards(w, v)
+	case *NotificationDelegatorRewards:
+		return EncodeNotificationDelegatorRewards(w, *v)
+	case NotificationProposalDeposit:
+		return EncodeNotificationProposalDeposit(w, v)
+	case *NotificationProposalDeposit:
+		return EncodeNotificationProposalDeposit(w, *v)
+	case NotificationProposalResult:
+		return EncodeNotificationProposalResult(w, v)
+	case *NotificationProposalResult:
+		return EncodeNotificationProposalResult(w, *v)
+	case NotificationProposalVote:
+		return EncodeNotificationProposalVote(w, v)
+	case *NotificationProposalVote:
+		return EncodeNotificationProposalVote(w, *v)
+	case NotificationSlash:
+		return EncodeNotificationSlash(w, v)
+	case *NotificationSlash:
+		return EncodeNotificationSlash(w, *v)
+	case NotificationSubmitProposal:
+		return EncodeNotificationSubmitProposal(w, v)
+	case *NotificationSubmitProposal:
+		return EncodeNotificationSubmitProposal(w, *v)
+	case NotificationTx:
+		return EncodeNotificationTx(w, v)
+	case *NotificationTx:
+		return EncodeNotificationTx(w, *v)
+	case NotificationUnlock:
+		return EncodeNotificationUnlock(w, v)
+	case *NotificationUnlock:
+		return EncodeNotificationUnlock(w, *v)
+	case NotificationValidatorCommission:
+		return EncodeNotificationValidatorCommission(w, v)
+	case *NotificationValidatorCommission:
+		return EncodeNotificationValidatorCommission(w, *v)
+	case NotificationValidatorSetUpdate:
+		return EncodeNotificationValidatorSetUpdate(w, v)
+	case *NotificationValidatorSetUpdate:
+		return EncodeNotificationValidatorSetUpdate(w, *v)
+	case NotificationVotingPeriodStart:
+		return EncodeNotificationVotingPeriodStart(w, v)
+	case *NotificationVotingPeriodStart:
+		return EncodeNotificationVotingPeriodStart(w, *v)
 	case Order:
 		return EncodeOrder(w, v)
 	case *Order:
@@ -8877,10 +11453,26 @@ func BareEncodeAny(w io.Writer, x interface{}) error {
 		return EncodeSupply(w, v)
 	case *Supply:
 		return EncodeSupply(w, *v)
+	case TallyResultRecord:
+		return EncodeTallyResultRecord(w, v)
+	case *TallyResultRecord:
+		return EncodeTallyResultRecord(w, *v)
 	case TextProposal:
 		return EncodeTextProposal(w, v)
 	case *TextProposal:
 		return EncodeTextProposal(w, *v)
+	case TransferRecord:
+		return EncodeTransferRecord(w, v)
+	case *TransferRecord:
+		return EncodeTransferRecord(w, *v)
+	case TxFeeInfo:
+		return EncodeTxFeeInfo(w, v)
+	case *TxFeeInfo:
+		return EncodeTxFeeInfo(w, *v)
+	case ValidatorUpdateRecord:
+		return EncodeValidatorUpdateRecord(w, v)
+	case *ValidatorUpdateRecord:
+		return EncodeValidatorUpdateRecord(w, *v)
 	case Vote:
 		return EncodeVote(w, v)
 	case *Vote:
@@ -8934,6 +11526,12 @@ func DecodeAny(bz []byte) (interface{}, int, error) {
 	case [4]byte{130, 76, 198, 17}:
 		v, n, err := DecodeDuplicateVoteEvidence(bz[4:])
 		return v, n + 4, err
+	case [4]byte{87, 242, 73, 118}:
+		v, n, err := DecodeFeeRecord(bz[4:])
+		return v, n + 4, err
+	case [4]byte{168, 214, 188, 50}:
+		v, n, err := DecodeGasPriceRecord(bz[4:])
+		return v, n + 4, err
 	case [4]byte{165, 152, 189, 47}:
 		v, n, err := DecodeInput(bz[4:])
 		return v, n + 4, err
@@ -9042,6 +11640,9 @@ func DecodeAny(bz []byte) (interface{}, int, error) {
 	case [4]byte{200, 224, 118, 175}:
 		v, n, err := DecodeMsgTransferOwnership(bz[4:])
 		return v, n + 4, err
+	case [4]byte{221, 229, 131, 96}:
+		v, n, err := DecodeMsgTypeCount(bz[4:])
+		return v, n + 4, err
 	case [4]byte{167, 165, 166, 227}:
 		v, n, err := DecodeMsgUnForbidAddr(bz[4:])
 		return v, n + 4, err
@@ -9066,6 +11667,57 @@ func DecodeAny(bz []byte) (interface{}, int, error) {
 	case [4]byte{18, 172, 190, 152}:
 		v, n, err := DecodeMsgWithdrawValidatorCommission(bz[4:])
 		return v, n + 4, err
+	case [4]byte{83, 224, 250, 93}:
+		v, n, err := DecodeNewHeightInfo(bz[4:])
+		return v, n + 4, err
+	case [4]byte{254, 170, 237, 194}:
+		v, n, err := DecodeNotificationBeginRedelegation(bz[4:])
+		return v, n + 4, err
+	case [4]byte{160, 47, 248, 154}:
+		v, n, err := DecodeNotificationBeginUnbonding(bz[4:])
+		return v, n + 4, err
+	case [4]byte{45, 90, 181, 233}:
+		v, n, err := DecodeNotificationBlockSummary(bz[4:])
+		return v, n + 4, err
+	case [4]byte{160, 81, 61, 92}:
+		v, n, err := DecodeNotificationCompleteRedelegation(bz[4:])
+		return v, n + 4, err
+	case [4]byte{63, 71, 143, 54}:
+		v, n, err := DecodeNotificationCompleteUnbonding(bz[4:])
+		return v, n + 4, err
+	case [4]byte{79, 46, 192, 231}:
+		v, n, err := DecodeNotificationDelegatorRewards(bz[4:])
+		return v, n + 4, err
+	case [4]byte{44, 144, 21, 215}:
+		v, n, err := DecodeNotificationProposalDeposit(bz[4:])
+		return v, n + 4, err
+	case [4]byte{216, 63, 18, 223}:
+		v, n, err := DecodeNotificationProposalResult(bz[4:])
+		return v, n + 4, err
+	case [4]byte{158, 20, 156, 188}:
+		v, n, err := DecodeNotificationProposalVote(bz[4:])
+		return v, n + 4, err
+	case [4]byte{20, 221, 69, 149}:
+		v, n, err := DecodeNotificationSlash(bz[4:])
+		return v, n + 4, err
+	case [4]byte{135, 88, 76, 14}:
+		v, n, err := DecodeNotificationSubmitProposal(bz[4:])
+		return v, n + 4, err
+	case [4]byte{233, 19, 77, 125}:
+		v, n, err := DecodeNotificationTx(bz[4:])
+		return v, n + 4, err
+	case [4]byte{201, 190, 59, 20}:
+		v, n, err := DecodeNotificationUnlock(bz[4:])
+		return v, n + 4, err
+	case [4]byte{192, 105, 82, 175}:
+		v, n, err := DecodeNotificationValidatorCommission(bz[4:])
+		return v, n + 4, err
+	case [4]byte{246, 192, 35, 1}:
+		v, n, err := DecodeNotificationValidatorSetUpdate(bz[4:])
+		return v, n + 4, err
+	case [4]byte{82, 218, 8, 164}:
+		v, n, err := DecodeNotificationVotingPeriodStart(bz[4:])
+		return v, n + 4, err
 	case [4]byte{57, 115, 63, 27}:
 		v, n, err := DecodeOrder(bz[4:])
 		return v, n + 4, err
@@ -9111,9 +11763,21 @@ func DecodeAny(bz []byte) (interface{}, int, error) {
 	case [4]byte{233, 209, 209, 86}:
 		v, n, err := DecodeSupply(bz[4:])
 		return v, n + 4, err
+	case [4]byte{20, 74, 254, 112}:
+		v, n, err := DecodeTallyResultRecord(bz[4:])
+		return v, n + 4, err
 	case [4]byte{169, 32, 176, 245}:
 		v, n, err := DecodeTextProposal(bz[4:])
 		return v, n + 4, err
+	case [4]byte{107, 88, 144, 13}:
+		v, n, err := DecodeTransferRecord(bz[4:])
+		return v, n + 4, err
+	case [4]byte{229, 54, 28, 217}:
+		v, n, err := DecodeTxFeeInfo(bz[4:])
+		return v, n + 4, err
+	case [4]byte{196, 176, 131, 229}:
+		v, n, err := DecodeValidatorUpdateRecord(bz[4:])
+		return v, n + 4, err
 	case [4]byte{113, 227, 24, 224}:
 		v, n, err := DecodeVote(bz[4:])
 		return v, n + 4, err
@@ -9149,6 +11813,10 @@ func BareDecodeAny(bz []byte, x interface{}) (n int, err error) {
 		*v, n, err = DecodeDelayedVestingAccount(bz)
 	case *DuplicateVoteEvidence:
 		*v, n, err = DecodeDuplicateVoteEvidence(bz)
+	case *FeeRecord:
+		*v, n, err = DecodeFeeRecord(bz)
+	case *GasPriceRecord:
+		*v, n, err = DecodeGasPriceRecord(bz)
 	case *Input:
 		*v, n, err = DecodeInput(bz)
 	case *LockedCoin:
@@ -9221,6 +11889,8 @@ func BareDecodeAny(bz []byte, x interface{}) (n int, err error) {
 		*v, n, err = DecodeMsgSubmitProposal(bz)
 	case *MsgTransferOwnership:
 		*v, n, err = DecodeMsgTransferOwnership(bz)
+	case *MsgTypeCount:
+		*v, n, err = DecodeMsgTypeCount(bz)
 	case *MsgUnForbidAddr:
 		*v, n, err = DecodeMsgUnForbidAddr(bz)
 	case *MsgUnForbidToken:
@@ -9237,6 +11907,40 @@ func BareDecodeAny(bz []byte, x interface{}) (n int, err error) {
 		*v, n, err = DecodeMsgWithdrawDelegatorReward(bz)
 	case *MsgWithdrawValidatorCommission:
 		*v, n, err = DecodeMsgWithdrawValidatorCommission(bz)
+	case *NewHeightInfo:
+		*v, n, err = DecodeNewHeightInfo(bz)
+	case *NotificationBeginRedelegation:
+		*v, n, err = DecodeNotificationBeginRedelegation(bz)
+	case *NotificationBeginUnbonding:
+		*v, n, err = DecodeNotificationBeginUnbonding(bz)
+	case *NotificationBlockSummary:
+		*v, n, err = DecodeNotificationBlockSummary(bz)
+	case *NotificationCompleteRedelegation:
+		*v, n, err = DecodeNotificationCompleteRedelegation(bz)
+	case *NotificationCompleteUnbonding:
+		*v, n, err = DecodeNotificationCompleteUnbonding(bz)
+	case *NotificationDelegatorRewards:
+		*v, n, err = DecodeNotificationDelegatorRewards(bz)
+	case *NotificationProposalDeposit:
+		*v, n, err = DecodeNotificationProposalDeposit(bz)
+	case *NotificationProposalResult:
+		*v, n, err = DecodeNotificationProposalResult(bz)
+	case *NotificationProposalVote:
+		*v, n, err = DecodeNotificationProposalVote(bz)
+	case *NotificationSlash:
+		*v, n, err = DecodeNotificationSlash(bz)
+	case *NotificationSubmitProposal:
+		*v, n, err = DecodeNotificationSubmitProposal(bz)
+	case *NotificationTx:
+		*v, n, err = DecodeNotificationTx(bz)
+	case *NotificationUnlock:
+		*v, n, err = DecodeNotificationUnlock(bz)
+	case *NotificationValidatorCommission:
+		*v, n, err = DecodeNotificationValidatorCommission(bz)
+	case *NotificationValidatorSetUpdate:
+		*v, n, err = DecodeNotificationValidatorSetUpdate(bz)
+	case *NotificationVotingPeriodStart:
+		*v, n, err = DecodeNotificationVotingPeriodStart(bz)
 	case *Order:
 		*v, n, err = DecodeOrder(bz)
 	case *Output:
@@ -9267,8 +11971,16 @@ func BareDecodeAny(bz []byte, x interface{}) (n int, err error) {
 		*v, n, err = DecodeStdTx(bz)
 	case *Supply:
 		*v, n, err = DecodeSupply(bz)
+	case *TallyResultRecord:
+		*v, n, err = DecodeTallyResultRecord(bz)
 	case *TextProposal:
 		*v, n, err = DecodeTextProposal(bz)
+	case *TransferRecord:
+		*v, n, err = DecodeTransferRecord(bz)
+	case *TxFeeInfo:
+		*v, n, err = DecodeTxFeeInfo(bz)
+	case *ValidatorUpdateRecord:
+		*v, n, err = DecodeValidatorUpdateRecord(bz)
 	case *Vote:
 		*v, n, err = DecodeVote(bz)
 	case *VoteOption:
@@ -9279,7 +11991,7 @@ func BareDecodeAny(bz []byte, x interface{}) (n int, err error) {
 	return
 } // end of DecodeVar
 func RandAny(r RandSrc) interface{} {
-	switch r.GetUint() % 73 {
+	switch r.GetUint() % 97 {
 	case 0:
 		return RandAccAddress(r)
 	case 1:
@@ -9303,128 +12015,176 @@ func RandAny(r RandSrc) interface{} {
 	case 10:
 		return RandDuplicateVoteEvidence(r)
 	case 11:
-		return RandInput(r)
+		return RandFeeRecord(r)
 	case 12:
-		return RandLockedCoin(r)
+		return RandGasPriceRecord(r)
 	case 13:
-		return RandMarketInfo(r)
+		return RandInput(r)
 	case 14:
-		return RandModuleAccount(r)
+		return RandLockedCoin(r)
 	case 15:
-		return RandMsgAddTokenWhitelist(r)
+		return RandMarketInfo(r)
 	case 16:
-		return RandMsgAliasUpdate(r)
+		return RandModuleAccount(r)
 	case 17:
-		return RandMsgBancorCancel(r)
+		return RandMsgAddTokenWhitelist(r)
 	case 18:
-		return RandMsgBancorInit(r)
+		return RandMsgAliasUpdate(r)
 	case 19:
-		return RandMsgBancorTrade(r)
+		return RandMsgBancorCancel(r)
 	case 20:
-		return RandMsgBeginRedelegate(r)
+		return RandMsgBancorInit(r)
 	case 21:
-		return RandMsgBurnToken(r)
+		return RandMsgBancorTrade(r)
 	case 22:
-		return RandMsgCancelOrder(r)
+		return RandMsgBeginRedelegate(r)
 	case 23:
-		return RandMsgCancelTradingPair(r)
+		return RandMsgBurnToken(r)
 	case 24:
-		return RandMsgCommentToken(r)
+		return RandMsgCancelOrder(r)
 	case 25:
-		return RandMsgCreateOrder(r)
+		return RandMsgCancelTradingPair(r)
 	case 26:
-		return RandMsgCreateTradingPair(r)
+		return RandMsgCommentToken(r)
 	case 27:
-		return RandMsgCreateValidator(r)
+		return RandMsgCreateOrder(r)
 	case 28:
-		return RandMsgDelegate(r)
+		return RandMsgCreateTradingPair(r)
 	case 29:
-		return RandMsgDeposit(r)
+		return RandMsgCreateValidator(r)
 	case 30:
-		return RandMsgDonateToCommunityPool(r)
+		return RandMsgDelegate(r)
 	case 31:
-		return RandMsgEditValidator(r)
+		return RandMsgDeposit(r)
 	case 32:
-		return RandMsgForbidAddr(r)
+		return RandMsgDonateToCommunityPool(r)
 	case 33:
-		return RandMsgForbidToken(r)
+		return RandMsgEditValidator(r)
 	case 34:
-		return RandMsgIssueToken(r)
+		return RandMsgForbidAddr(r)
 	case 35:
-		return RandMsgMintToken(r)
+		return RandMsgForbidToken(r)
 	case 36:
-		return RandMsgModifyPricePrecision(r)
+		return RandMsgIssueToken(r)
 	case 37:
-		return RandMsgModifyTokenInfo(r)
+		return RandMsgMintToken(r)
 	case 38:
-		return RandMsgMultiSend(r)
+		return RandMsgModifyPricePrecision(r)
 	case 39:
-		return RandMsgMultiSendX(r)
+		return RandMsgModifyTokenInfo(r)
 	case 40:
-		return RandMsgRemoveTokenWhitelist(r)
+		return RandMsgMultiSend(r)
 	case 41:
-		return RandMsgSend(r)
+		return RandMsgMultiSendX(r)
 	case 42:
-		return RandMsgSendX(r)
+		return RandMsgRemoveTokenWhitelist(r)
 	case 43:
-		return RandMsgSetMemoRequired(r)
+		return RandMsgSend(r)
 	case 44:
-		return RandMsgSetWithdrawAddress(r)
+		return RandMsgSendX(r)
 	case 45:
-		return RandMsgSubmitProposal(r)
+		return RandMsgSetMemoRequired(r)
 	case 46:
-		return RandMsgTransferOwnership(r)
+		return RandMsgSetWithdrawAddress(r)
 	case 47:
-		return RandMsgUnForbidAddr(r)
+		return RandMsgSubmitProposal(r)
 	case 48:
-		return RandMsgUnForbidToken(r)
+		return RandMsgTransferOwnership(r)
 	case 49:
-		return RandMsgUndelegate(r)
+		return RandMsgTypeCount(r)
 	case 50:
-		return RandMsgUnjail(r)
+		return RandMsgUnForbidAddr(r)
 	case 51:
-		return RandMsgVerifyInvariant(r)
+		return RandMsgUnForbidToken(r)
 	case 52:
-		return RandMsgVote(r)
+		return RandMsgUndelegate(r)
 	case 53:
-		return RandMsgWithdrawDelegatorReward(r)
+		return RandMsgUnjail(r)
 	case 54:
-		return RandMsgWithdrawValidatorCommission(r)
+		return RandMsgVerifyInvariant(r)
 	case 55:
-		return RandOrder(r)
+		return RandMsgVote(r)
 	case 56:
-		return RandOutput(r)
+		return RandMsgWithdrawDelegatorReward(r)
 	case 57:
-		return RandParamChange(r)
+		return RandMsgWithdrawValidatorCommission(r)
 	case 58:
-		return RandParameterChangeProposal(r)
+		return RandNewHeightInfo(r)
 	case 59:
-		return RandPrivKeyEd25519(r)
+		return RandNotificationBeginRedelegation(r)
 	case 60:
-		return RandPrivKeySecp256k1(r)
+		return RandNotificationBeginUnbonding(r)
 	case 61:
-		return RandPubKeyEd25519(r)
+		return RandNotificationBlockSummary(r)
 	case 62:
-		return RandPubKeyMultisigThreshold(r)
+		return RandNotificationCompleteRedelegation(r)
 	case 63:
-		return RandPubKeySecp256k1(r)
+		return RandNotificationCompleteUnbonding(r)
 	case 64:
-		return RandSignedMsgType(r)
+		return RandNotificationDelegatorRewards(r)
 	case 65:
-		return RandSoftwareUpgradeProposal(r)
+		return RandNotificationProposalDeposit(r)
 	case 66:
-		return RandState(r)
+		return RandNotificationProposalResult(r)
 	case 67:
-		return RandStdSignature(r)
+		return RandNotificationProposalVote(r)
 	case 68:
-		return RandStdTx(r)
+		return RandNotificationSlash(r)
 	case 69:
-		return RandSupply(r)
+		return RandNotificationSubmitProposal(r)
 	case 70:
-		return RandTextProposal(r)
+		return RandNotificationTx(r)
 	case 71:
-		return RandVote(r)
+		return RandNotificationUnlock(r)
 	case 72:
+		return RandNotificationValidatorCommission(r)
+	case 73:
+		return RandNotificationValidatorSetUpdate(r)
+	case 74:
+		return RandNotificationVotingPeriodStart(r)
+	case 75:
+		return RandOrder(r)
+	case 76:
+		return RandOutput(r)
+	case 77:
+		return RandParamChange(r)
+	case 78:
+		return RandParameterChangeProposal(r)
+	case 79:
+		return RandPrivKeyEd25519(r)
+	case 80:
+		return RandPrivKeySecp256k1(r)
+	case 81:
+		return RandPubKeyEd25519(r)
+	case 82:
+		return RandPubKeyMultisigThreshold(r)
+	case 83:
+		return RandPubKeySecp256k1(r)
+	case 84:
+		return RandSignedMsgType(r)
+	case 85:
+		return RandSoftwareUpgradeProposal(r)
+	case 86:
+		return RandState(r)
+	case 87:
+		return RandStdSignature(r)
+	case 88:
+		return RandStdTx(r)
+	case 89:
+		return RandSupply(r)
+	case 90:
+		return RandTallyResultRecord(r)
+	case 91:
+		return RandTextProposal(r)
+	case 92:
+		return RandTransferRecord(r)
+	case 93:
+		return RandTxFeeInfo(r)
+	case 94:
+		return RandValidatorUpdateRecord(r)
+	case 95:
+		return RandVote(r)
+	case 96:
 		return RandVoteOption(r)
 	default:
 		panic("Unknown Type.")
@@ -9464,6 +12224,30 @@ func GetSupportList() []string {
 		"github.com/coinexchain/cet-sdk/modules/market/internal/types.MsgCreateTradingPair",
 		"github.com/coinexchain/cet-sdk/modules/market/internal/types.MsgModifyPricePrecision",
 		"github.com/coinexchain/cet-sdk/modules/market/internal/types.Order",
+		"github.com/coinexchain/dex/app/notification.FeeRecord",
+		"github.com/coinexchain/dex/app/notification.GasPriceRecord",
+		"github.com/coinexchain/dex/app/notification.MsgTypeCount",
+		"github.com/coinexchain/dex/app/notification.NewHeightInfo",
+		"github.com/coinexchain/dex/app/notification.NotificationBeginRedelegation",
+		"github.com/coinexchain/dex/app/notification.NotificationBeginUnbonding",
+		"github.com/coinexchain/dex/app/notification.NotificationBlockSummary",
+		"github.com/coinexchain/dex/app/notification.NotificationCompleteRedelegation",
+		"github.com/coinexchain/dex/app/notification.NotificationCompleteUnbonding",
+		"github.com/coinexchain/dex/app/notification.NotificationDelegatorRewards",
+		"github.com/coinexchain/dex/app/notification.NotificationProposalDeposit",
+		"github.com/coinexchain/dex/app/notification.NotificationProposalResult",
+		"github.com/coinexchain/dex/app/notification.NotificationProposalVote",
+		"github.com/coinexchain/dex/app/notification.NotificationSlash",
+		"github.com/coinexchain/dex/app/notification.NotificationSubmitProposal",
+		"github.com/coinexchain/dex/app/notification.NotificationTx",
+		"github.com/coinexchain/dex/app/notification.NotificationUnlock",
+		"github.com/coinexchain/dex/app/notification.NotificationValidatorCommission",
+		"github.com/coinexchain/dex/app/notification.NotificationValidatorSetUpdate",
+		"github.com/coinexchain/dex/app/notification.NotificationVotingPeriodStart",
+		"github.com/coinexchain/dex/app/notification.TallyResultRecord",
+		"github.com/coinexchain/dex/app/notification.TransferRecord",
+		"github.com/coinexchain/dex/app/notification.TxFeeInfo",
+		"github.com/coinexchain/dex/app/notification.ValidatorUpdateRecord",
 		"github.com/cosmos/cosmos-sdk/types.AccAddress",
 		"github.com/cosmos/cosmos-sdk/types.Coin",
 		"github.com/cosmos/cosmos-sdk/types.Msg",
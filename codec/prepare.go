@@ -159,6 +159,31 @@ func GenerateCodecFile(w io.Writer) {
 		{Alias: "MsgCommentToken", Value: MsgCommentToken{}},
 		{Alias: "State", Value: State{}},
 		{Alias: "MsgAliasUpdate", Value: MsgAliasUpdate{}},
+
+		{Alias: "NewHeightInfo", Value: NewHeightInfo{}},
+		{Alias: "TransferRecord", Value: TransferRecord{}},
+		{Alias: "NotificationTx", Value: NotificationTx{}},
+		{Alias: "NotificationBeginRedelegation", Value: NotificationBeginRedelegation{}},
+		{Alias: "NotificationBeginUnbonding", Value: NotificationBeginUnbonding{}},
+		{Alias: "NotificationCompleteRedelegation", Value: NotificationCompleteRedelegation{}},
+		{Alias: "NotificationCompleteUnbonding", Value: NotificationCompleteUnbonding{}},
+		{Alias: "NotificationSlash", Value: NotificationSlash{}},
+		{Alias: "NotificationValidatorCommission", Value: NotificationValidatorCommission{}},
+		{Alias: "NotificationDelegatorRewards", Value: NotificationDelegatorRewards{}},
+		{Alias: "ValidatorUpdateRecord", Value: ValidatorUpdateRecord{}},
+		{Alias: "NotificationValidatorSetUpdate", Value: NotificationValidatorSetUpdate{}},
+		{Alias: "NotificationSubmitProposal", Value: NotificationSubmitProposal{}},
+		{Alias: "NotificationProposalDeposit", Value: NotificationProposalDeposit{}},
+		{Alias: "NotificationProposalVote", Value: NotificationProposalVote{}},
+		{Alias: "NotificationVotingPeriodStart", Value: NotificationVotingPeriodStart{}},
+		{Alias: "TallyResultRecord", Value: TallyResultRecord{}},
+		{Alias: "NotificationProposalResult", Value: NotificationProposalResult{}},
+		{Alias: "NotificationUnlock", Value: NotificationUnlock{}},
+		{Alias: "MsgTypeCount", Value: MsgTypeCount{}},
+		{Alias: "FeeRecord", Value: FeeRecord{}},
+		{Alias: "NotificationBlockSummary", Value: NotificationBlockSummary{}},
+		{Alias: "GasPriceRecord", Value: GasPriceRecord{}},
+		{Alias: "TxFeeInfo", Value: TxFeeInfo{}},
 	}
 
 	extraImports := []string{`"time"`, `sdk "github.com/cosmos/cosmos-sdk/types"`}
@@ -28,6 +28,7 @@ import (
 	distrx "github.com/coinexchain/cet-sdk/modules/distributionx"
 	"github.com/coinexchain/cet-sdk/modules/incentive"
 	"github.com/coinexchain/cet-sdk/modules/market"
+	"github.com/coinexchain/dex/app/notification"
 )
 
 type (
@@ -115,4 +116,29 @@ type (
 	MsgCommentToken          = comment.MsgCommentToken
 	State                    = incentive.State
 	MsgAliasUpdate           = alias.MsgAliasUpdate
+
+	NewHeightInfo                    = notification.NewHeightInfo
+	TransferRecord                   = notification.TransferRecord
+	NotificationTx                   = notification.NotificationTx
+	NotificationBeginRedelegation    = notification.NotificationBeginRedelegation
+	NotificationBeginUnbonding       = notification.NotificationBeginUnbonding
+	NotificationCompleteRedelegation = notification.NotificationCompleteRedelegation
+	NotificationCompleteUnbonding    = notification.NotificationCompleteUnbonding
+	NotificationSlash                = notification.NotificationSlash
+	NotificationValidatorCommission  = notification.NotificationValidatorCommission
+	NotificationDelegatorRewards     = notification.NotificationDelegatorRewards
+	ValidatorUpdateRecord            = notification.ValidatorUpdateRecord
+	NotificationValidatorSetUpdate   = notification.NotificationValidatorSetUpdate
+	NotificationSubmitProposal       = notification.NotificationSubmitProposal
+	NotificationProposalDeposit      = notification.NotificationProposalDeposit
+	NotificationProposalVote         = notification.NotificationProposalVote
+	NotificationVotingPeriodStart    = notification.NotificationVotingPeriodStart
+	TallyResultRecord                = notification.TallyResultRecord
+	NotificationProposalResult       = notification.NotificationProposalResult
+	NotificationUnlock               = notification.NotificationUnlock
+	MsgTypeCount                     = notification.MsgTypeCount
+	FeeRecord                        = notification.FeeRecord
+	NotificationBlockSummary         = notification.NotificationBlockSummary
+	GasPriceRecord                   = notification.GasPriceRecord
+	TxFeeInfo                        = notification.TxFeeInfo
 )